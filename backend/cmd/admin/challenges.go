@@ -0,0 +1,117 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+	"github.com/go-ctf-platform/backend/internal/realtime"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"github.com/go-ctf-platform/backend/internal/storage"
+)
+
+// newChallengeImportService wires the services a challenge import needs
+func newChallengeImportService(cfg *config.Config) (*services.ChallengeImportService, error) {
+	fileStorage, err := storage.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file storage: %w", err)
+	}
+
+	challengeRepo := repositories.NewChallengeRepository()
+	if err := challengeRepo.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create challenge indexes: %w", err)
+	}
+	userRepo := repositories.NewUserRepository()
+	fileService := services.NewFileService(challengeRepo, fileStorage, cfg.MaxUploadSizeMB)
+	challengeService := services.NewChallengeService(
+		challengeRepo,
+		repositories.NewSubmissionRepository(),
+		repositories.NewTeamRepository(),
+		fileService,
+		userRepo,
+		repositories.NewCheatReportRepository(),
+		repositories.NewHintUnlockRepository(),
+		repositories.NewSolveRepository(),
+		repositories.NewAwardRepository(),
+		services.NewEventService(repositories.NewEventRepository()),
+		realtime.NewBroker(),
+	)
+	return services.NewChallengeImportService(challengeService, challengeRepo, fileService), nil
+}
+
+func challengeImportCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	positional, err := parseFlags(newFlagSet("challenge import"), args, 1)
+	if err != nil {
+		return nil, "", err
+	}
+	path := positional[0]
+
+	var fsys fs.FS
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer archive.Close()
+		fsys = archive
+	} else {
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return nil, "", fmt.Errorf("%s is not a directory or zip archive", path)
+		}
+		fsys = os.DirFS(path)
+	}
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	importService, err := newChallengeImportService(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	result, err := importService.Import(fsys)
+	if err != nil {
+		if result != nil {
+			err = fmt.Errorf("%w (created %d, updated %d before the failure)", err, len(result.Created), len(result.Updated))
+		}
+		return nil, "", err
+	}
+
+	summary := fmt.Sprintf("Created: %d %v\nUpdated: %d %v\nFiles uploaded: %d, removed: %d\n\n✅ Challenges imported",
+		len(result.Created), result.Created, len(result.Updated), result.Updated, result.FilesUploaded, result.FilesRemoved)
+	return result, summary, nil
+}
+
+func challengeExportCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	positional, err := parseFlags(newFlagSet("challenge export"), args, 1)
+	if err != nil {
+		return nil, "", err
+	}
+	path := positional[0]
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	importService, err := newChallengeImportService(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create archive: %w", err)
+	}
+	if err := importService.Export(out); err != nil {
+		out.Close()
+		os.Remove(path)
+		return nil, "", fmt.Errorf("export failed: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return map[string]string{"output": path}, fmt.Sprintf("✅ Challenges exported to %s", path), nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+)
+
+// Exit codes of the non-interactive commands
+const (
+	exitOK      = 0
+	exitFailure = 1 // The command ran and failed
+	exitUsage   = 2 // Unknown command or bad arguments
+)
+
+const usage = `Usage: admin <command> [flags]
+
+Run without arguments for the interactive menu.
+
+Commands:
+  user create --username NAME --email EMAIL (--password-stdin | --password PASS) [--role admin|user]
+  user promote USER
+  user demote USER
+  user ban USER [--reason TEXT]
+  user unban USER
+  user list
+  challenge import DIR|FILE.zip
+  challenge export FILE.zip
+  scoreboard export [--output FILE|-]
+
+USER is a username or email. With --json, commands print their result (or
+{"error": ...}) as JSON on stdout. Exit codes: 0 success, 1 failure, 2 usage error.`
+
+// usageError is a command line mistake; it exits with exitUsage
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// commandFunc runs a subcommand. It returns the result printed with --json and
+// the summary printed otherwise; either may be empty if the command wrote its own output.
+type commandFunc func(cfg *config.Config, args []string) (interface{}, string, error)
+
+var commands = map[string]map[string]commandFunc{
+	"user": {
+		"create":  userCreateCommand,
+		"promote": userPromoteCommand,
+		"demote":  userDemoteCommand,
+		"ban":     userBanCommand,
+		"unban":   userUnbanCommand,
+		"list":    userListCommand,
+	},
+	"challenge": {
+		"import": challengeImportCommand,
+		"export": challengeExportCommand,
+	},
+	"scoreboard": {
+		"export": scoreboardExportCommand,
+	},
+}
+
+// runCommand runs "admin <group> <command> [flags]" and returns the exit code
+func runCommand(cfg *config.Config, args []string) int {
+	jsonOutput := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--json" || arg == "-json" {
+			jsonOutput = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	if len(rest) == 1 && (rest[0] == "help" || rest[0] == "-h" || rest[0] == "--help") {
+		fmt.Println(usage)
+		return exitOK
+	}
+	if len(rest) < 2 || commands[rest[0]][rest[1]] == nil {
+		return fail(jsonOutput, usageError("unknown command: "+strings.Join(rest, " ")))
+	}
+
+	result, summary, err := commands[rest[0]][rest[1]](cfg, rest[2:])
+	if err != nil {
+		return fail(jsonOutput, err)
+	}
+
+	if jsonOutput && result != nil {
+		if err := printJSON(result); err != nil {
+			return fail(false, err)
+		}
+	} else if summary != "" {
+		fmt.Println(summary)
+	}
+	return exitOK
+}
+
+// fail reports a command error and returns its exit code
+func fail(jsonOutput bool, err error) int {
+	code := exitFailure
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		code = exitUsage
+	}
+
+	if jsonOutput {
+		printJSON(map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if code == exitUsage {
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintln(os.Stderr, usage)
+		}
+	}
+	return code
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// newFlagSet returns a flag set that reports errors instead of exiting
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags parses flags placed before, between or after the positional
+// arguments, which it returns. wantArgs is the number of positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string, wantArgs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(fs.Name() + ": " + err.Error())
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != wantArgs {
+		return nil, usageError(fmt.Sprintf("%s: expected %d argument(s), got %d", fs.Name(), wantArgs, len(positional)))
+	}
+	return positional, nil
+}
+
+// readPassword reads the password from the first line of stdin
+func readPassword() (string, error) {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
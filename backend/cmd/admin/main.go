@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -9,19 +10,49 @@ import (
 
 	"github.com/go-ctf-platform/backend/internal/config"
 	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
 	"github.com/go-ctf-platform/backend/internal/repositories"
 	"github.com/go-ctf-platform/backend/internal/services"
 )
 
 var adminService *services.AdminService
+var scoreboardService *services.ScoreboardService
 
-func main() {
-	cfg := config.LoadConfig()
-	database.ConnectDB(cfg.MongoURI, cfg.DBName)
+// connect opens the database and initializes the services the commands use
+func connect(cfg *config.Config) error {
+	if err := database.Connect(cfg.MongoURI, cfg.DBName); err != nil {
+		return err
+	}
+	// Redis is optional; when available the session and scoreboard caches are invalidated
+	database.ConnectRedis(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
 
 	// Initialize repository and service layers
 	userRepo := repositories.NewUserRepository()
-	adminService = services.NewAdminService(userRepo)
+	adminService = services.NewAdminService(userRepo, services.NewSessionService(repositories.NewSessionRepository(), repositories.NewRefreshTokenRepository()))
+	scoreboardService = services.NewScoreboardService(
+		userRepo,
+		repositories.NewSubmissionRepository(),
+		repositories.NewChallengeRepository(),
+		repositories.NewTeamRepository(),
+		repositories.NewHintUnlockRepository(),
+		repositories.NewSolveRepository(),
+		repositories.NewAwardRepository(),
+		services.NewEventService(repositories.NewEventRepository()),
+	)
+	return nil
+}
+
+func main() {
+	cfg := config.LoadConfig()
+
+	// Subcommands for scripts; the interactive menu stays the default
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(cfg, os.Args[1:]))
+	}
+
+	if err := connect(cfg); err != nil {
+		log.Fatal(err)
+	}
 
 	reader := bufio.NewReader(os.Stdin)
 
@@ -33,7 +64,8 @@ func main() {
 	fmt.Println("2. Promote User to Admin")
 	fmt.Println("3. Demote Admin to User")
 	fmt.Println("4. List All Users")
-	fmt.Println("5. Exit")
+	fmt.Println("5. Export CTFtime Scoreboard")
+	fmt.Println("6. Exit")
 	fmt.Println()
 	fmt.Print("Choose an option: ")
 
@@ -50,6 +82,8 @@ func main() {
 	case "4":
 		listAllUsers()
 	case "5":
+		exportCTFtimeScoreboard(reader)
+	case "6":
 		fmt.Println("Goodbye!")
 		os.Exit(0)
 	default:
@@ -130,12 +164,18 @@ func listAllUsers() {
 		log.Fatal("Failed to fetch users:", err)
 	}
 
-	fmt.Printf("%-20s %-30s %-10s %-15s\n", "USERNAME", "EMAIL", "ROLE", "VERIFIED")
-	fmt.Println(strings.Repeat("─", 80))
+	fmt.Println(formatUsers(users))
+}
+
+// formatUsers renders users as a table
+func formatUsers(users []models.User) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-20s %-30s %-10s %-15s\n", "USERNAME", "EMAIL", "ROLE", "VERIFIED")
+	b.WriteString(strings.Repeat("─", 80) + "\n")
 
 	if len(users) == 0 {
-		fmt.Println("No users found.")
-		return
+		b.WriteString("No users found.")
+		return b.String()
 	}
 
 	for _, user := range users {
@@ -147,8 +187,78 @@ func listAllUsers() {
 		if user.Role == "admin" {
 			roleDisplay = "🔑 admin"
 		}
-		fmt.Printf("%-20s %-30s %-10s %-15s\n", user.Username, user.Email, roleDisplay, verified)
+		if user.Banned {
+			roleDisplay += " (banned)"
+		}
+		fmt.Fprintf(&b, "%-20s %-30s %-10s %-15s\n", user.Username, user.Email, roleDisplay, verified)
+	}
+
+	fmt.Fprintf(&b, "\nTotal users: %d", len(users))
+	return b.String()
+}
+
+func exportCTFtimeScoreboard(reader *bufio.Reader) {
+	fmt.Println("\n=== Export CTFtime Scoreboard ===")
+
+	fmt.Print("Output file (default: ctftime.json): ")
+	path, _ := reader.ReadString('\n')
+	path = strings.TrimSpace(path)
+	if path == "" {
+		path = "ctftime.json"
+	}
+
+	teams, err := writeCTFtimeScoreboard(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("\n✅ CTFtime scoreboard with %d teams written to %s\n", teams, path)
+}
+
+// writeCTFtimeScoreboard writes the final standings to path ("-" for stdout)
+// and returns the number of teams
+func writeCTFtimeScoreboard(path string) (int, error) {
+	// Final standings, including solves made after the scoreboard freeze
+	scores, err := scoreboardService.GetLiveTeamScoreboard()
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute scoreboard: %w", err)
+	}
+
+	data, err := json.MarshalIndent(services.NewCTFtimeScoreboard(scores), "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode scoreboard: %w", err)
+	}
+	if path == "-" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+	} else {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write scoreboard: %w", err)
+	}
+
+	return len(scores), nil
+}
+
+func scoreboardExportCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	fs := newFlagSet("scoreboard export")
+	path := fs.String("output", "ctftime.json", "output file, or - for stdout")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return nil, "", err
 	}
 
-	fmt.Printf("\nTotal users: %d\n", len(users))
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	teams, err := writeCTFtimeScoreboard(*path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if *path == "-" {
+		// The scoreboard itself is the output
+		return nil, "", nil
+	}
+	result := map[string]interface{}{"output": *path, "teams": teams}
+	return result, fmt.Sprintf("✅ CTFtime scoreboard with %d teams written to %s", teams, *path), nil
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+	"github.com/go-ctf-platform/backend/internal/models"
+)
+
+func userCreateCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	fs := newFlagSet("user create")
+	username := fs.String("username", "", "username")
+	email := fs.String("email", "", "email address")
+	password := fs.String("password", "", "password (visible in the process list; prefer --password-stdin)")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	role := fs.String("role", "admin", "admin or user")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return nil, "", err
+	}
+
+	if *username == "" || *email == "" {
+		return nil, "", usageError("user create: --username and --email are required")
+	}
+	if *passwordStdin == (*password != "") {
+		return nil, "", usageError("user create: use exactly one of --password and --password-stdin")
+	}
+	if *passwordStdin {
+		var err error
+		if *password, err = readPassword(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	user, err := adminService.CreateUser(*username, *email, *password, *role)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, fmt.Sprintf("✅ User '%s' created with role %s", user.Username, user.Role), nil
+}
+
+// userIdentifierArg parses a command that takes a single username or email
+func userIdentifierArg(name string, args []string) (string, error) {
+	fs := newFlagSet(name)
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return "", err
+	}
+	return positional[0], nil
+}
+
+func userPromoteCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	identifier, err := userIdentifierArg("user promote", args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	user, err := adminService.PromoteToAdmin(identifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, fmt.Sprintf("✅ User '%s' promoted to admin", user.Username), nil
+}
+
+func userDemoteCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	identifier, err := userIdentifierArg("user demote", args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	user, err := adminService.DemoteToUser(identifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, fmt.Sprintf("✅ User '%s' demoted to regular user", user.Username), nil
+}
+
+func userBanCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	fs := newFlagSet("user ban")
+	reason := fs.String("reason", "", "reason for the ban")
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	user, err := adminService.BanUser(positional[0], *reason)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, fmt.Sprintf("✅ User '%s' banned", user.Username), nil
+}
+
+func userUnbanCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	identifier, err := userIdentifierArg("user unban", args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	user, err := adminService.UnbanUser(identifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, fmt.Sprintf("✅ User '%s' unbanned", user.Username), nil
+}
+
+func userListCommand(cfg *config.Config, args []string) (interface{}, string, error) {
+	if _, err := parseFlags(newFlagSet("user list"), args, 0); err != nil {
+		return nil, "", err
+	}
+
+	if err := connect(cfg); err != nil {
+		return nil, "", err
+	}
+	users, err := adminService.GetAllUsers()
+	if err != nil {
+		return nil, "", err
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+
+	return users, formatUsers(users), nil
+}
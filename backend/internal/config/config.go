@@ -22,6 +22,31 @@ type Config struct {
 	RedisAddr    string
 	RedisPassword string
 	RedisDB      int
+
+	// File storage for challenge attachments
+	StorageDriver    string // "local" or "s3"
+	StorageLocalPath string
+	S3Endpoint       string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3Region         string
+	S3UseSSL         bool
+	MaxUploadSizeMB  int64
+
+	// Issuer shown in authenticator apps for TOTP two-factor authentication
+	TOTPIssuer string
+
+	// OAuth2/OIDC login; each provider is enabled when its client ID is set
+	OAuthCallbackBaseURL string // Public URL of this API; callbacks are <base>/auth/oauth/<provider>/callback
+	GitHubClientID       string
+	GitHubClientSecret   string
+	GoogleClientID       string
+	GoogleClientSecret   string
+	OIDCIssuerURL        string // Any OpenID Connect issuer, e.g. Keycloak or a local mock server
+	OIDCClientID         string
+	OIDCClientSecret     string
+	OIDCDisplayName      string
 }
 
 func LoadConfig() *Config {
@@ -32,6 +57,8 @@ func LoadConfig() *Config {
 
 	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	s3UseSSL, _ := strconv.ParseBool(getEnv("S3_USE_SSL", "true"))
+	maxUploadSizeMB, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE_MB", "50"), 10, 64)
 
 	return &Config{
 		Port:         getEnv("PORT", "8080"),
@@ -47,6 +74,28 @@ func LoadConfig() *Config {
 		RedisAddr:    getEnv("REDIS_ADDR", "localhost:6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 		RedisDB:      redisDB,
+
+		StorageDriver:    getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalPath: getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+		S3Endpoint:       getEnv("S3_ENDPOINT", ""),
+		S3Bucket:         getEnv("S3_BUCKET", "ctf-files"),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3UseSSL:         s3UseSSL,
+		MaxUploadSizeMB:  maxUploadSizeMB,
+
+		TOTPIssuer: getEnv("TOTP_ISSUER", "RootAccess CTF"),
+
+		OAuthCallbackBaseURL: getEnv("OAUTH_CALLBACK_BASE_URL", "http://localhost:8080"),
+		GitHubClientID:       getEnv("GITHUB_CLIENT_ID", ""),
+		GitHubClientSecret:   getEnv("GITHUB_CLIENT_SECRET", ""),
+		GoogleClientID:       getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:   getEnv("GOOGLE_CLIENT_SECRET", ""),
+		OIDCIssuerURL:        getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:         getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:     getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCDisplayName:      getEnv("OIDC_DISPLAY_NAME", "Single Sign-On"),
 	}
 }
 
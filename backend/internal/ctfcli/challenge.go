@@ -0,0 +1,177 @@
+// Package ctfcli reads and writes challenges in the challenge.yml format used by
+// CTFd's ctfcli, so authors can keep challenges in git.
+//
+// Besides the standard keys, a few platform-specific keys are understood and
+// written (slug, difficulty, flag_mode, flag_format, min_team_score,
+// blood_bonuses, links, release_at and extra.scoring); ctfcli ignores them. Static flags are
+// only stored as hashes, so exported flags carry a hash instead of content and
+// can only be imported back into this platform.
+package ctfcli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of a challenge spec file; FileNameAlt is also accepted on import
+const (
+	FileName    = "challenge.yml"
+	FileNameAlt = "challenge.yaml"
+)
+
+// Challenge types
+const (
+	TypeStandard = "standard"
+	TypeDynamic  = "dynamic"
+)
+
+// CTFd dynamic decay functions
+const (
+	FunctionLinear      = "linear"
+	FunctionLogarithmic = "logarithmic"
+)
+
+// Challenge is one challenge.yml document
+type Challenge struct {
+	Name         string        `yaml:"name"`
+	Slug         string        `yaml:"slug,omitempty"`
+	Author       string        `yaml:"author,omitempty"`
+	Category     string        `yaml:"category"`
+	Description  string        `yaml:"description"`
+	Difficulty   string        `yaml:"difficulty,omitempty"`
+	Value        int           `yaml:"value"`
+	Type         string        `yaml:"type"`
+	Extra        *Extra        `yaml:"extra,omitempty"`
+	FlagMode     string        `yaml:"flag_mode,omitempty"`
+	FlagFormat   string        `yaml:"flag_format,omitempty"`
+	Flags        []Flag        `yaml:"flags,omitempty"`
+	Tags         []Tag         `yaml:"tags,omitempty"`
+	Files        []string      `yaml:"files,omitempty"` // Paths relative to the challenge.yml
+	Links        []string      `yaml:"links,omitempty"` // External file URLs
+	Hints        []Hint        `yaml:"hints,omitempty"`
+	Requirements *Requirements `yaml:"requirements,omitempty"`
+	MinTeamScore int           `yaml:"min_team_score,omitempty"`
+	BloodBonuses []int         `yaml:"blood_bonuses,omitempty"`
+	State        string        `yaml:"state,omitempty"`      // hidden or visible; draft and archived are also understood
+	ReleaseAt    *time.Time    `yaml:"release_at,omitempty"` // Scheduled release of a visible challenge
+	Version      string        `yaml:"version,omitempty"`
+}
+
+// Extra holds the scoring parameters of dynamic challenges
+type Extra struct {
+	Initial  int    `yaml:"initial"`
+	Decay    int    `yaml:"decay"`
+	Minimum  int    `yaml:"minimum"`
+	Function string `yaml:"function,omitempty"` // CTFd: logarithmic (default) or linear
+	Scoring  string `yaml:"scoring,omitempty"`  // Platform scoring function, takes precedence over function
+}
+
+// Flag is a flag entry, written either as a plain string or as a mapping
+type Flag struct {
+	Type    string `yaml:"type"`
+	Content string `yaml:"content,omitempty"`
+	Data    string `yaml:"data,omitempty"` // "case_insensitive" for static flags
+	Hash    string `yaml:"hash,omitempty"` // SHA-256 of the flag, written on export instead of content
+}
+
+// FlagDataCaseInsensitive marks a static flag as case insensitive
+const FlagDataCaseInsensitive = "case_insensitive"
+
+func (f *Flag) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		f.Type = "static"
+		return node.Decode(&f.Content)
+	}
+	type plain Flag
+	if err := node.Decode((*plain)(f)); err != nil {
+		return err
+	}
+	if f.Type == "" {
+		f.Type = "static"
+	}
+	return nil
+}
+
+// Tag is a tag entry, written either as a plain string or as {value: ...}
+type Tag string
+
+func (t *Tag) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var value string
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		*t = Tag(value)
+		return nil
+	}
+	var tag struct {
+		Value string `yaml:"value"`
+	}
+	if err := node.Decode(&tag); err != nil {
+		return err
+	}
+	*t = Tag(tag.Value)
+	return nil
+}
+
+// Hint is a hint entry, written either as a plain (free) string or as {content, cost}
+type Hint struct {
+	Content string `yaml:"content"`
+	Cost    int    `yaml:"cost"`
+}
+
+func (h *Hint) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		h.Cost = 0
+		return node.Decode(&h.Content)
+	}
+	type plain Hint
+	return node.Decode((*plain)(h))
+}
+
+// Requirements lists the challenges (by slug or name) that must be solved first.
+// It is written either as a plain list or as {prerequisites, anonymize}; in CTFd,
+// locked challenges are hidden unless anonymize is set.
+type Requirements struct {
+	Prerequisites []string `yaml:"prerequisites"`
+	Anonymize     bool     `yaml:"anonymize"`
+}
+
+func (r *Requirements) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		r.Anonymize = false
+		return node.Decode(&r.Prerequisites)
+	}
+	type plain Requirements
+	return node.Decode((*plain)(r))
+}
+
+// Parse reads a challenge.yml document
+func Parse(data []byte) (*Challenge, error) {
+	var c Challenge
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if c.Type == "" {
+		c.Type = TypeStandard
+	}
+	if c.Type != TypeStandard && c.Type != TypeDynamic {
+		return nil, fmt.Errorf("unsupported challenge type %q", c.Type)
+	}
+	if c.State != "" && !models.IsValidVisibility(c.State) {
+		return nil, fmt.Errorf("unsupported state %q", c.State)
+	}
+	return &c, nil
+}
+
+// Marshal writes a challenge.yml document
+func Marshal(c *Challenge) ([]byte, error) {
+	return yaml.Marshal(c)
+}
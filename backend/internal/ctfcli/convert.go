@@ -0,0 +1,215 @@
+package ctfcli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+)
+
+// DefaultDifficulty is used for imported challenges that do not set one
+const DefaultDifficulty = "medium"
+
+// IsURL reports whether a files entry is an external link rather than a bundled file
+func IsURL(file string) bool {
+	return strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://")
+}
+
+// FromChallenge converts a challenge to its challenge.yml form. Attachments are
+// listed as dist/<name>; prerequisites are the slugs of the required challenges.
+func FromChallenge(c *models.Challenge, slug string, prerequisites []string) *Challenge {
+	spec := &Challenge{
+		Name:         c.Title,
+		Slug:         slug,
+		Category:     c.Category,
+		Description:  c.Description,
+		Difficulty:   c.Difficulty,
+		Value:        c.MaxPoints,
+		Type:         TypeStandard,
+		Links:        c.Files,
+		MinTeamScore: c.MinTeamScore,
+		BloodBonuses: c.BloodBonuses,
+		State:        c.VisibilityState(),
+	}
+	if !c.ReleaseAt.IsZero() {
+		releaseAt := c.ReleaseAt.UTC()
+		spec.ReleaseAt = &releaseAt
+	}
+
+	if c.Scoring != models.ScoringStatic {
+		function := FunctionLogarithmic
+		if c.Scoring == models.ScoringLinear {
+			function = FunctionLinear
+		}
+		spec.Type = TypeDynamic
+		spec.Extra = &Extra{
+			Initial:  c.MaxPoints,
+			Decay:    c.Decay,
+			Minimum:  c.MinPoints,
+			Function: function,
+			Scoring:  c.Scoring,
+		}
+	}
+
+	if c.IsDynamicFlag() {
+		spec.FlagMode = models.FlagModeDynamic
+		spec.FlagFormat = c.FlagFormat
+	} else {
+		for _, f := range c.StaticFlags() {
+			switch f.Type {
+			case models.FlagTypeRegex:
+				spec.Flags = append(spec.Flags, Flag{Type: "regex", Content: f.Pattern})
+			case models.FlagTypeCaseInsensitive:
+				spec.Flags = append(spec.Flags, Flag{Type: "static", Data: FlagDataCaseInsensitive, Hash: f.Hash})
+			default:
+				spec.Flags = append(spec.Flags, Flag{Type: "static", Hash: f.Hash})
+			}
+		}
+	}
+
+	for _, tag := range c.Tags {
+		spec.Tags = append(spec.Tags, Tag(tag))
+	}
+
+	hints := make([]models.Hint, len(c.Hints))
+	copy(hints, c.Hints)
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Order < hints[j].Order
+	})
+	for _, h := range hints {
+		spec.Hints = append(spec.Hints, Hint{Content: h.Content, Cost: h.Cost})
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range c.Attachments {
+		name := file.Name
+		if seen[name] {
+			name = file.ID.Hex() + "-" + name
+		}
+		seen[name] = true
+		spec.Files = append(spec.Files, "dist/"+name)
+	}
+
+	if len(prerequisites) > 0 {
+		spec.Requirements = &Requirements{
+			Prerequisites: prerequisites,
+			Anonymize:     !c.HideLocked,
+		}
+	}
+
+	return spec
+}
+
+// scoringOf maps the spec's type and extra settings to a scoring function
+func (spec *Challenge) scoringOf() string {
+	if spec.Type != TypeDynamic {
+		return models.ScoringStatic
+	}
+	if spec.Extra != nil && spec.Extra.Scoring != "" {
+		return spec.Extra.Scoring
+	}
+	if spec.Extra != nil && spec.Extra.Function == FunctionLinear {
+		return models.ScoringLinear
+	}
+	// CTFd's "logarithmic" decay is the quadratic formula
+	return models.ScoringQuadratic
+}
+
+// buildFlags converts the spec's flags; static flags may carry content or a hash
+func (spec *Challenge) buildFlags() ([]models.Flag, error) {
+	flags := make([]models.Flag, 0, len(spec.Flags))
+	for _, f := range spec.Flags {
+		switch f.Type {
+		case "static":
+			flagType := models.FlagTypeStatic
+			if f.Data == FlagDataCaseInsensitive {
+				flagType = models.FlagTypeCaseInsensitive
+			}
+			if f.Hash != "" {
+				flags = append(flags, models.Flag{Type: flagType, Hash: strings.ToLower(f.Hash)})
+				continue
+			}
+			if f.Content == "" {
+				return nil, fmt.Errorf("static flag needs content or hash")
+			}
+			flag, err := models.NewFlag(flagType, f.Content)
+			if err != nil {
+				return nil, err
+			}
+			flags = append(flags, flag)
+		case "regex":
+			pattern := f.Content
+			if f.Data == FlagDataCaseInsensitive {
+				pattern = "(?i)" + pattern
+			}
+			flag, err := models.NewFlag(models.FlagTypeRegex, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex flag: %w", err)
+			}
+			flags = append(flags, flag)
+		default:
+			return nil, fmt.Errorf("unsupported flag type %q", f.Type)
+		}
+	}
+	return flags, nil
+}
+
+// ToChallenge converts the spec to a challenge. Hints get no IDs, attachments and
+// prerequisites are left for the importer, which resolves them against the database.
+func (spec *Challenge) ToChallenge() (*models.Challenge, error) {
+	flags, err := spec.buildFlags()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &models.Challenge{
+		Slug:         spec.Slug,
+		Title:        spec.Name,
+		Description:  spec.Description,
+		Category:     spec.Category,
+		Difficulty:   spec.Difficulty,
+		MaxPoints:    spec.Value,
+		MinPoints:    spec.Value,
+		Scoring:      spec.scoringOf(),
+		Flags:        flags,
+		FlagMode:     spec.FlagMode,
+		FlagFormat:   spec.FlagFormat,
+		Files:        append([]string{}, spec.Links...),
+		MinTeamScore: spec.MinTeamScore,
+		BloodBonuses: spec.BloodBonuses,
+		Visibility:   spec.State,
+	}
+	if spec.ReleaseAt != nil {
+		c.ReleaseAt = spec.ReleaseAt.UTC()
+	}
+	if c.Difficulty == "" {
+		c.Difficulty = DefaultDifficulty
+	}
+	if spec.Type == TypeDynamic && spec.Extra != nil {
+		if spec.Extra.Initial > 0 {
+			c.MaxPoints = spec.Extra.Initial
+		}
+		c.MinPoints = spec.Extra.Minimum
+		c.Decay = spec.Extra.Decay
+	}
+	if spec.Requirements != nil {
+		c.HideLocked = !spec.Requirements.Anonymize
+	}
+
+	for _, file := range spec.Files {
+		if IsURL(file) {
+			c.Files = append(c.Files, file)
+		}
+	}
+	for _, tag := range spec.Tags {
+		if t := strings.TrimSpace(string(tag)); t != "" {
+			c.Tags = append(c.Tags, t)
+		}
+	}
+	for i, h := range spec.Hints {
+		c.Hints = append(c.Hints, models.Hint{Content: h.Content, Cost: h.Cost, Order: i + 1})
+	}
+
+	return c, nil
+}
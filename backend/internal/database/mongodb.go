@@ -2,16 +2,29 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var DB *mongo.Database
 
+// TransactionsSupported is true when connected to a replica set or sharded cluster
+var TransactionsSupported bool
+
 func ConnectDB(uri, dbName string) {
+	if err := Connect(uri, dbName); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Connect opens the database like ConnectDB, but returns connection errors
+// instead of exiting, for callers that report errors themselves
+func Connect(uri, dbName string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -22,16 +35,52 @@ func ConnectDB(uri, dbName string) {
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		log.Fatal("Failed to ping MongoDB:", err)
+		return fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
 	DB = client.Database(dbName)
+	TransactionsSupported = supportsTransactions(ctx)
+	if !TransactionsSupported {
+		log.Println("MongoDB is a standalone server; multi-document transactions are disabled")
+	}
 	log.Println("Connected to MongoDB successfully")
+	return nil
+}
+
+// supportsTransactions checks whether the server is a replica set member or a mongos
+func supportsTransactions(ctx context.Context) bool {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := DB.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid"
+}
+
+// WithTransaction runs fn inside a transaction when the deployment supports them,
+// and directly otherwise. fn may be retried, so it must be safe to run again.
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !TransactionsSupported {
+		return fn(ctx)
+	}
+
+	session, err := DB.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
 }
 
 func GetCollection(collectionName string) *mongo.Collection {
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/services"
+)
+
+type APITokenHandler struct {
+	apiTokenService *services.APITokenService
+}
+
+func NewAPITokenHandler(apiTokenService *services.APITokenService) *APITokenHandler {
+	return &APITokenHandler{
+		apiTokenService: apiTokenService,
+	}
+}
+
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays int      `json:"expires_in_days"` // 0 means the token never expires
+}
+
+// GetTokens lists the current user's personal API tokens
+func (h *APITokenHandler) GetTokens(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	tokens, err := h.apiTokenService.GetUserTokens(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"scopes": models.APITokenScopes,
+	})
+}
+
+// CreateToken creates a personal API token; its value is only returned here
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	var req CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	token, secret, err := h.apiTokenService.CreateToken(userID.(string), req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Token created. Copy it now; it will not be shown again.",
+		"token":   secret,
+		"details": token,
+	})
+}
+
+// RevokeToken deletes one of the current user's personal API tokens
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.apiTokenService.RevokeToken(userID.(string), c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revoked"})
+}
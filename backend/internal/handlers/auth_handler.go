@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-ctf-platform/backend/internal/services"
@@ -28,6 +30,11 @@ type LoginRequest struct {
 	Password        string `json:"password" binding:"required"`
 }
 
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
 type VerifyEmailRequest struct {
 	Token string `json:"token" binding:"required"`
 }
@@ -108,7 +115,7 @@ func (h *AuthHandler) ResendVerification(c *gin.Context) {
 	})
 }
 
-// Login authenticates a user and sets JWT token in HTTP-only cookie
+// Login authenticates a user and sets the access and refresh tokens in HTTP-only cookies
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -116,42 +123,139 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, userInfo, err := h.authService.Login(req.UsernameOrEmail, req.Password)
+	result, err := h.authService.Login(req.UsernameOrEmail, req.Password, clientInfo(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Set HTTP-only cookie with JWT token
-	c.SetCookie(
-		"auth_token", // name
-		token,        // value
-		7*24*60*60,   // maxAge (7 days in seconds)
-		"/",          // path
-		"",           // domain (empty = current domain)
-		false,        // secure (set to true in production with HTTPS)
-		true,         // httpOnly
-	)
+	if result.TwoFactorRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"message":             "Enter your two-factor code to finish logging in.",
+			"two_factor_required": true,
+			"challenge_token":     result.ChallengeToken,
+		})
+		return
+	}
 
+	setAuthCookies(c, result)
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful!",
-		"user":    userInfo,
+		"message":    "Login successful!",
+		"user":       result.User,
+		"expires_in": int(services.AccessTokenTTL.Seconds()),
 	})
 }
 
-// Logout clears the authentication cookie
-func (h *AuthHandler) Logout(c *gin.Context) {
-	// Clear the cookie by setting maxAge to -1
+// LoginTwoFactor completes a two-factor login with the challenge token from Login
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.LoginWithTwoFactor(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		status := http.StatusUnauthorized
+		if errors.Is(err, services.ErrTwoFactorLocked) {
+			status = http.StatusTooManyRequests
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	setAuthCookies(c, result)
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Login successful!",
+		"user":       result.User,
+		"expires_in": int(services.AccessTokenTTL.Seconds()),
+	})
+}
+
+// clientInfo describes the device a request comes from, for its session
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// refreshCookiePath limits the refresh token cookie to the endpoints that use it
+const refreshCookiePath = "/auth"
+
+// setAuthCookies sets the HTTP-only cookies holding the access and refresh tokens
+func setAuthCookies(c *gin.Context, result *services.LoginResult) {
 	c.SetCookie(
-		"auth_token",
-		"",
-		-1,
-		"/",
+		"auth_token",                           // name
+		result.Token,                           // value
+		int(services.AccessTokenTTL.Seconds()), // maxAge
+		"/",                                    // path
+		"",                                     // domain (empty = current domain)
+		false,                                  // secure (set to true in production with HTTPS)
+		true,                                   // httpOnly
+	)
+	c.SetCookie(
+		"refresh_token",
+		result.RefreshToken,
+		int(services.RefreshTokenTTL.Seconds()),
+		refreshCookiePath,
 		"",
 		false,
 		true,
 	)
+}
+
+// clearAuthCookies removes both token cookies by setting maxAge to -1
+func clearAuthCookies(c *gin.Context) {
+	c.SetCookie("auth_token", "", -1, "/", "", false, true)
+	c.SetCookie("refresh_token", "", -1, refreshCookiePath, "", false, true)
+}
+
+// Refresh exchanges the refresh token cookie for a new access and refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	refreshToken, err := c.Cookie("refresh_token")
+	if err != nil || refreshToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token required"})
+		return
+	}
+
+	result, err := h.authService.Refresh(refreshToken)
+	if err != nil {
+		clearAuthCookies(c)
+		if errors.Is(err, services.ErrInvalidRefreshToken) ||
+			errors.Is(err, services.ErrRefreshTokenReused) ||
+			errors.Is(err, services.ErrSessionRevoked) ||
+			errors.Is(err, services.ErrUserBanned) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setAuthCookies(c, result)
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Session refreshed",
+		"user":       result.User,
+		"expires_in": int(services.AccessTokenTTL.Seconds()),
+	})
+}
+
+// Logout revokes the session and clears the authentication cookies
+func (h *AuthHandler) Logout(c *gin.Context) {
+	accessToken, err := c.Cookie("auth_token")
+	if err != nil || accessToken == "" {
+		accessToken = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	refreshToken, _ := c.Cookie("refresh_token")
+	if accessToken != "" || refreshToken != "" {
+		if err := h.authService.Logout(accessToken, refreshToken); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
+	clearAuthCookies(c)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logged out successfully!",
 	})
@@ -211,12 +315,12 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.ChangePassword(userID.(string), req.OldPassword, req.NewPassword); err != nil {
+	if err := h.authService.ChangePassword(userID.(string), c.GetString("session_id"), req.OldPassword, req.NewPassword); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Password changed successfully!",
+		"message": "Password changed successfully! Your other sessions have been signed out.",
 	})
 }
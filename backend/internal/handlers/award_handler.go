@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AwardHandler struct {
+	awardService *services.AwardService
+}
+
+func NewAwardHandler(awardService *services.AwardService) *AwardHandler {
+	return &AwardHandler{
+		awardService: awardService,
+	}
+}
+
+// CreateAwardRequest represents the request body for creating an award or penalty
+type CreateAwardRequest struct {
+	TeamID string `json:"team_id"`
+	UserID string `json:"user_id"`
+	Points int    `json:"points" binding:"required"` // Negative for a penalty
+	Reason string `json:"reason" binding:"required"`
+}
+
+// UpdateAwardRequest represents the request body for updating an award
+type UpdateAwardRequest struct {
+	Points int    `json:"points" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CreateAward grants or deducts points from a team or user (admin only)
+func (h *AwardHandler) CreateAward(c *gin.Context) {
+	var req CreateAwardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get admin user ID from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	adminID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	award, err := h.awardService.CreateAward(adminID, req.TeamID, req.UserID, req.Points, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Award created successfully",
+		"award":   award,
+	})
+}
+
+// GetAllAwards returns every award and penalty, newest first (admin only)
+func (h *AwardHandler) GetAllAwards(c *gin.Context) {
+	awards, err := h.awardService.GetAllAwards()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, awards)
+}
+
+// GetAward returns a single award (admin only)
+func (h *AwardHandler) GetAward(c *gin.Context) {
+	award, err := h.awardService.GetAward(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, award)
+}
+
+// UpdateAward changes the points and reason of an award (admin only)
+func (h *AwardHandler) UpdateAward(c *gin.Context) {
+	var req UpdateAwardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	award, err := h.awardService.UpdateAward(c.Param("id"), req.Points, req.Reason)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "award not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Award updated successfully",
+		"award":   award,
+	})
+}
+
+// DeleteAward removes an award, reverting its points (admin only)
+func (h *AwardHandler) DeleteAward(c *gin.Context) {
+	if err := h.awardService.DeleteAward(c.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "award not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Award deleted successfully"})
+}
+
+// GetTeamAwards returns the awards and penalties of a team to its members
+func (h *AwardHandler) GetTeamAwards(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	awards, err := h.awardService.GetTeamAwards(c.Param("id"), userID.(string), isAdmin(c))
+	if err != nil {
+		status := http.StatusForbidden
+		if err.Error() == "team not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, awards)
+}
@@ -1,35 +1,132 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
 	"github.com/go-ctf-platform/backend/internal/services"
-	"github.com/go-ctf-platform/backend/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ChallengeHandler struct {
 	challengeService *services.ChallengeService
+	eventService     *services.EventService
 }
 
-func NewChallengeHandler(challengeService *services.ChallengeService) *ChallengeHandler {
+func NewChallengeHandler(challengeService *services.ChallengeService, eventService *services.EventService) *ChallengeHandler {
 	return &ChallengeHandler{
 		challengeService: challengeService,
+		eventService:     eventService,
 	}
 }
 
+// isAdmin reports whether the current user is an admin; admins are not bound by the event schedule
+func isAdmin(c *gin.Context) bool {
+	role, _ := c.Get("role")
+	return role == "admin"
+}
+
 type CreateChallengeRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Category    string   `json:"category" binding:"required"`
-	Difficulty  string   `json:"difficulty" binding:"required"`
-	MaxPoints   int      `json:"max_points" binding:"required"`
-	MinPoints   int      `json:"min_points" binding:"required"`
-	Decay       int      `json:"decay" binding:"required"`
-	Flag        string   `json:"flag" binding:"required"`
-	Files       []string `json:"files"`
+	Slug          string        `json:"slug"` // Stable key used by challenge.yml import; omit on update to keep the current one
+	Title         string        `json:"title" binding:"required"`
+	Description   string        `json:"description" binding:"required"`
+	Category      string        `json:"category" binding:"required"`
+	Tags          []string      `json:"tags"`
+	Difficulty    string        `json:"difficulty" binding:"required"`
+	MaxPoints     int           `json:"max_points" binding:"required"`
+	MinPoints     int           `json:"min_points"`  // Ignored by static scoring
+	Decay         int           `json:"decay"`       // Solves until min_points; ignored by static scoring
+	Scoring       string        `json:"scoring"`     // static, linear, logarithmic or quadratic (default); omit on update to keep the current one
+	Flag          string        `json:"flag"`        // Shorthand for a single static flag
+	Flags         []FlagRequest `json:"flags"`       // Accepted flags for static challenges; omit on update to keep the current ones
+	FlagMode      string        `json:"flag_mode"`   // static (default) or dynamic
+	FlagFormat    string        `json:"flag_format"` // Wrapper for dynamic flags, e.g. "flag{%s}"
+	Files         []string      `json:"files"`
+	Hints         []HintRequest `json:"hints"`
+	Prerequisites []string      `json:"prerequisites"`  // IDs of challenges that must be solved first
+	MinTeamScore  int           `json:"min_team_score"` // Score needed before the challenge unlocks
+	HideLocked    bool          `json:"hide_locked"`    // Hide the challenge while locked instead of redacting it
+	BloodBonuses  []int         `json:"blood_bonuses"`  // Bonus points for the first, second and third solver
+	Visibility    string        `json:"visibility"`     // draft, hidden, visible (default) or archived; omit on update to keep the current one
+	ReleaseAt     *time.Time    `json:"release_at"`     // Keep a visible challenge from players until then; omit for no scheduled release
+}
+
+// FlagRequest describes one accepted flag in a challenge create/update request
+type FlagRequest struct {
+	Type  string `json:"type"` // static (default), case_insensitive or regex
+	Value string `json:"value" binding:"required"`
+}
+
+// buildFlags converts the flag and flags request fields to models, hashing non-regex flags
+func buildFlags(flag string, reqs []FlagRequest) ([]models.Flag, error) {
+	flags := make([]models.Flag, 0, len(reqs)+1)
+	if flag != "" {
+		f, _ := models.NewFlag(models.FlagTypeStatic, flag)
+		flags = append(flags, f)
+	}
+	for _, req := range reqs {
+		f, err := models.NewFlag(req.Type, req.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s flag: %w", req.Type, err)
+		}
+		flags = append(flags, f)
+	}
+	return flags, nil
+}
+
+// parsePrerequisites converts prerequisite challenge IDs from a request
+func parsePrerequisites(ids []string) ([]primitive.ObjectID, error) {
+	prerequisites := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prerequisite ID: %s", id)
+		}
+		prerequisites = append(prerequisites, oid)
+	}
+	return prerequisites, nil
+}
+
+func hexIDs(ids []primitive.ObjectID) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id.Hex())
+	}
+	return result
+}
+
+// HintRequest describes a hint in a challenge create/update request.
+// Existing hints keep their ID so previous unlocks stay valid.
+type HintRequest struct {
+	ID      string `json:"id"`
+	Content string `json:"content" binding:"required"`
+	Cost    int    `json:"cost" binding:"min=0"`
+	Order   int    `json:"order"`
+}
+
+// buildHints converts hint requests to models, assigning IDs to new hints
+func buildHints(reqs []HintRequest) []models.Hint {
+	hints := make([]models.Hint, 0, len(reqs))
+	for _, req := range reqs {
+		id, err := primitive.ObjectIDFromHex(req.ID)
+		if err != nil {
+			id = primitive.NewObjectID()
+		}
+		hints = append(hints, models.Hint{
+			ID:      id,
+			Content: req.Content,
+			Cost:    req.Cost,
+			Order:   req.Order,
+		})
+	}
+	return hints
 }
 
 func (h *ChallengeHandler) CreateChallenge(c *gin.Context) {
@@ -39,23 +136,47 @@ func (h *ChallengeHandler) CreateChallenge(c *gin.Context) {
 		return
 	}
 
-	// Hash the flag before storing
-	flagHash := utils.HashFlag(req.Flag)
+	// Hash the flags before storing
+	flags, err := buildFlags(req.Flag, req.Flags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prerequisites, err := parsePrerequisites(req.Prerequisites)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	challenge := &models.Challenge{
-		Title:       req.Title,
-		Description: req.Description,
-		Category:    req.Category,
-		Difficulty:  req.Difficulty,
-		MaxPoints:   req.MaxPoints,
-		MinPoints:   req.MinPoints,
-		Decay:       req.Decay,
-		FlagHash:    flagHash,
-		Files:       req.Files,
+		Slug:          req.Slug,
+		Title:         req.Title,
+		Description:   req.Description,
+		Category:      req.Category,
+		Tags:          req.Tags,
+		Difficulty:    req.Difficulty,
+		MaxPoints:     req.MaxPoints,
+		MinPoints:     req.MinPoints,
+		Decay:         req.Decay,
+		Scoring:       req.Scoring,
+		Flags:         flags,
+		FlagMode:      req.FlagMode,
+		FlagFormat:    req.FlagFormat,
+		Files:         req.Files,
+		Hints:         buildHints(req.Hints),
+		Prerequisites: prerequisites,
+		MinTeamScore:  req.MinTeamScore,
+		HideLocked:    req.HideLocked,
+		BloodBonuses:  req.BloodBonuses,
+		Visibility:    req.Visibility,
+	}
+	if req.ReleaseAt != nil {
+		challenge.ReleaseAt = req.ReleaseAt.UTC()
 	}
 
 	if err := h.challengeService.CreateChallenge(challenge); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -70,23 +191,47 @@ func (h *ChallengeHandler) UpdateChallenge(c *gin.Context) {
 		return
 	}
 
-	// Hash the flag before storing
-	flagHash := utils.HashFlag(req.Flag)
+	// Hash the flags before storing
+	flags, err := buildFlags(req.Flag, req.Flags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prerequisites, err := parsePrerequisites(req.Prerequisites)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	challenge := &models.Challenge{
-		Title:       req.Title,
-		Description: req.Description,
-		Category:    req.Category,
-		Difficulty:  req.Difficulty,
-		MaxPoints:   req.MaxPoints,
-		MinPoints:   req.MinPoints,
-		Decay:       req.Decay,
-		FlagHash:    flagHash,
-		Files:       req.Files,
+		Slug:          req.Slug,
+		Title:         req.Title,
+		Description:   req.Description,
+		Category:      req.Category,
+		Tags:          req.Tags,
+		Difficulty:    req.Difficulty,
+		MaxPoints:     req.MaxPoints,
+		MinPoints:     req.MinPoints,
+		Decay:         req.Decay,
+		Scoring:       req.Scoring,
+		Flags:         flags,
+		FlagMode:      req.FlagMode,
+		FlagFormat:    req.FlagFormat,
+		Files:         req.Files,
+		Hints:         buildHints(req.Hints),
+		Prerequisites: prerequisites,
+		MinTeamScore:  req.MinTeamScore,
+		HideLocked:    req.HideLocked,
+		BloodBonuses:  req.BloodBonuses,
+		Visibility:    req.Visibility,
+	}
+	if req.ReleaseAt != nil {
+		challenge.ReleaseAt = req.ReleaseAt.UTC()
 	}
 
 	if err := h.challengeService.UpdateChallenge(id, challenge); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -106,86 +251,374 @@ func (h *ChallengeHandler) DeleteChallenge(c *gin.Context) {
 
 // ChallengeResponse is the response struct for challenges (for admin view)
 type ChallengeAdminResponse struct {
-	ID            string   `json:"id"`
-	Title         string   `json:"title"`
-	Description   string   `json:"description"`
-	Category      string   `json:"category"`
-	Difficulty    string   `json:"difficulty"`
-	MaxPoints     int      `json:"max_points"`
-	MinPoints     int      `json:"min_points"`
-	Decay         int      `json:"decay"`
-	SolveCount    int      `json:"solve_count"`
-	CurrentPoints int      `json:"current_points"`
-	Files         []string `json:"files"`
+	ID            string                 `json:"id"`
+	Slug          string                 `json:"slug,omitempty"`
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description"`
+	Category      string                 `json:"category"`
+	Tags          []string               `json:"tags"`
+	Difficulty    string                 `json:"difficulty"`
+	MaxPoints     int                    `json:"max_points"`
+	MinPoints     int                    `json:"min_points"`
+	FlagMode      string                 `json:"flag_mode"`
+	FlagFormat    string                 `json:"flag_format,omitempty"`
+	Flags         []FlagAdminView        `json:"flags"`
+	Decay         int                    `json:"decay"`
+	Scoring       string                 `json:"scoring"`
+	SolveCount    int                    `json:"solve_count"`
+	CurrentPoints int                    `json:"current_points"`
+	PointsPreview []int                  `json:"points_preview"` // Value after each of the next N solves
+	Files         []string               `json:"files"`
+	Attachments   []models.ChallengeFile `json:"attachments"`
+	Hints         []models.Hint          `json:"hints"`
+	Prerequisites []string               `json:"prerequisites"`
+	MinTeamScore  int                    `json:"min_team_score"`
+	HideLocked    bool                   `json:"hide_locked"`
+	BloodBonuses  []int                  `json:"blood_bonuses"`
+	Visibility    string                 `json:"visibility"`
+	ReleaseAt     *time.Time             `json:"release_at"`
+	Released      bool                   `json:"released"` // Whether players can currently see it
+}
+
+// FlagAdminView shows a flag to admins; only regex patterns are stored in plaintext
+type FlagAdminView struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+func flagAdminViews(challenge *models.Challenge) []FlagAdminView {
+	flags := challenge.StaticFlags()
+	views := make([]FlagAdminView, 0, len(flags))
+	for _, f := range flags {
+		views = append(views, FlagAdminView{Type: f.Type, Pattern: f.Pattern})
+	}
+	return views
+}
+
+// defaultPreviewSolves is how many upcoming solves the points preview covers by default
+const defaultPreviewSolves = 10
+
+// previewSolves reads the ?preview=N query parameter (1-100)
+func previewSolves(c *gin.Context) (int, error) {
+	n, err := strconv.Atoi(c.DefaultQuery("preview", strconv.Itoa(defaultPreviewSolves)))
+	if err != nil || n < 1 || n > 100 {
+		return 0, errors.New("preview must be between 1 and 100")
+	}
+	return n, nil
 }
 
 // GetAllChallengesWithFlags returns all challenges for admin (no flag hash exposed)
 func (h *ChallengeHandler) GetAllChallengesWithFlags(c *gin.Context) {
+	preview, err := previewSolves(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	challenges, err := h.challengeService.GetAllChallenges()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	now := time.Now()
 	var result []ChallengeAdminResponse
 	for _, ch := range challenges {
+		var releaseAt *time.Time
+		if !ch.ReleaseAt.IsZero() {
+			releaseAt = &ch.ReleaseAt
+		}
 		result = append(result, ChallengeAdminResponse{
 			ID:            ch.ID.Hex(),
+			Slug:          ch.Slug,
 			Title:         ch.Title,
 			Description:   ch.Description,
 			Category:      ch.Category,
+			Tags:          ch.Tags,
 			Difficulty:    ch.Difficulty,
 			MaxPoints:     ch.MaxPoints,
 			MinPoints:     ch.MinPoints,
+			FlagMode:      ch.FlagMode,
+			FlagFormat:    ch.FlagFormat,
+			Flags:         flagAdminViews(&ch),
 			Decay:         ch.Decay,
+			Scoring:       ch.Scoring,
 			SolveCount:    ch.SolveCount,
 			CurrentPoints: ch.CurrentPoints(),
+			PointsPreview: ch.PointsPreview(preview),
 			Files:         ch.Files,
+			Attachments:   ch.Attachments,
+			Hints:         ch.Hints,
+			Prerequisites: hexIDs(ch.Prerequisites),
+			MinTeamScore:  ch.MinTeamScore,
+			HideLocked:    ch.HideLocked,
+			BloodBonuses:  ch.BloodBonuses,
+			Visibility:    ch.VisibilityState(),
+			ReleaseAt:     releaseAt,
+			Released:      ch.IsReleased(now),
 		})
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
-// ChallengePublicResponse is the response struct for public challenge view
+// ScoringPreviewRequest describes unsaved scoring settings to preview
+type ScoringPreviewRequest struct {
+	Scoring    string `json:"scoring"`
+	MaxPoints  int    `json:"max_points" binding:"required"`
+	MinPoints  int    `json:"min_points"`
+	Decay      int    `json:"decay"`
+	SolveCount int    `json:"solve_count"` // Solves so far; the preview starts after them
+	Solves     int    `json:"solves"`      // Number of solves to preview (default 10)
+}
+
+// PreviewScoring returns the points curve for scoring settings before they are saved (admin only)
+func (h *ChallengeHandler) PreviewScoring(c *gin.Context) {
+	var req ScoringPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Scoring == "" {
+		req.Scoring = models.ScoringQuadratic
+	}
+	if !models.IsValidScoringFunction(req.Scoring) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scoring function"})
+		return
+	}
+	if req.Solves == 0 {
+		req.Solves = defaultPreviewSolves
+	}
+	if req.Solves < 1 || req.Solves > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "solves must be between 1 and 100"})
+		return
+	}
+
+	challenge := &models.Challenge{
+		Scoring:    req.Scoring,
+		MaxPoints:  req.MaxPoints,
+		MinPoints:  req.MinPoints,
+		Decay:      req.Decay,
+		SolveCount: req.SolveCount,
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scoring":        challenge.Scoring,
+		"current_points": challenge.CurrentPoints(),
+		"points_preview": challenge.PointsPreview(req.Solves),
+	})
+}
+
+// ChallengePublicResponse is the response struct for public challenge view.
+// Locked challenges are redacted: no description or files, only what unlocks them.
 type ChallengePublicResponse struct {
-	ID            string   `json:"id"`
-	Title         string   `json:"title"`
-	Description   string   `json:"description"`
-	Category      string   `json:"category"`
-	Difficulty    string   `json:"difficulty"`
-	MaxPoints     int      `json:"max_points"`
-	CurrentPoints int      `json:"current_points"`
-	SolveCount    int      `json:"solve_count"`
-	Files         []string `json:"files"`
+	ID            string                 `json:"id"`
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description"`
+	Category      string                 `json:"category"`
+	Tags          []string               `json:"tags,omitempty"`
+	Difficulty    string                 `json:"difficulty"`
+	MaxPoints     int                    `json:"max_points"`
+	CurrentPoints int                    `json:"current_points"`
+	SolveCount    int                    `json:"solve_count"`
+	Files         []string               `json:"files"`
+	Attachments   []models.ChallengeFile `json:"attachments"` // Download via /challenges/:id/files/:fileId
+	Locked        bool                   `json:"locked"`
+	Prerequisites []string               `json:"prerequisites,omitempty"`
+	MinTeamScore  int                    `json:"min_team_score,omitempty"`
+	BloodBonuses  []int                  `json:"blood_bonuses,omitempty"`
+	Archived      bool                   `json:"archived,omitempty"` // Listed, but no longer accepts flags or hint unlocks
+	Solved        bool                   `json:"solved"`             // Solved by the player's team
 }
 
+// newChallengePublicResponse builds the player view of a challenge. A nil access
+// means no locking or visibility rules apply (admins). It returns false if the
+// challenge must be hidden.
+func newChallengePublicResponse(ch *models.Challenge, access *services.ChallengeAccess) (ChallengePublicResponse, bool) {
+	response := ChallengePublicResponse{
+		ID:            ch.ID.Hex(),
+		Title:         ch.Title,
+		Description:   ch.Description,
+		Category:      ch.Category,
+		Tags:          ch.Tags,
+		Difficulty:    ch.Difficulty,
+		MaxPoints:     ch.MaxPoints,
+		CurrentPoints: ch.CurrentPoints(),
+		SolveCount:    ch.SolveCount,
+		Files:         ch.Files,
+		Attachments:   ch.Attachments,
+		Prerequisites: hexIDs(ch.Prerequisites),
+		MinTeamScore:  ch.MinTeamScore,
+		BloodBonuses:  ch.BloodBonuses,
+		Archived:      ch.VisibilityState() == models.VisibilityArchived,
+	}
+	if access != nil {
+		response.Solved = access.IsSolved(ch.ID)
+	}
+
+	if access != nil && !ch.IsReleased(time.Now()) {
+		return response, false
+	}
+	if access == nil || access.IsUnlocked(ch) {
+		return response, true
+	}
+	if ch.HideLocked {
+		return response, false
+	}
+
+	response.Locked = true
+	response.Description = ""
+	response.Files = nil
+	response.Attachments = nil
+	return response, true
+}
+
+// lockStatus maps an EnsureUnlocked or EnsureOpen error to a status code
+func lockStatus(err error) int {
+	if errors.Is(err, services.ErrChallengeLocked) || errors.Is(err, services.ErrChallengeArchived) {
+		return http.StatusForbidden
+	}
+	return http.StatusNotFound
+}
+
+// challengeAccess returns the current user's unlock state, or nil for admins
+func (h *ChallengeHandler) challengeAccess(c *gin.Context) (*services.ChallengeAccess, error) {
+	if isAdmin(c) {
+		return nil, nil
+	}
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	return h.challengeService.GetChallengeAccess(userID)
+}
+
+// maxSearchLength bounds the text search query of the challenge list
+const maxSearchLength = 100
+
+// challengeSorts maps the sort query parameter to a repository order and its default direction
+var challengeSorts = map[string]struct {
+	field      string
+	descending bool
+}{
+	"points":   {repositories.ChallengeSortPoints, true},
+	"solves":   {repositories.ChallengeSortSolves, true},
+	"title":    {repositories.ChallengeSortTitle, false},
+	"category": {repositories.ChallengeSortCategory, false},
+}
+
+// parseChallengeFilter reads the challenge list filters from the query string
+func parseChallengeFilter(c *gin.Context) (repositories.ChallengeFilter, string) {
+	filter := repositories.ChallengeFilter{
+		Category:   c.Query("category"),
+		Difficulty: c.Query("difficulty"),
+		Search:     strings.TrimSpace(c.Query("q")),
+	}
+
+	// Tags may be repeated (?tag=a&tag=b) or comma separated; challenges must have all of them
+	for _, value := range c.QueryArray("tag") {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	if len(filter.Search) > maxSearchLength {
+		return filter, "q must be at most " + strconv.Itoa(maxSearchLength) + " characters"
+	}
+
+	if value := c.Query("solved"); value != "" {
+		solved, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, "solved must be true or false"
+		}
+		filter.Solved = &solved
+	}
+
+	if value := c.Query("sort"); value != "" {
+		order, ok := challengeSorts[value]
+		if !ok {
+			return filter, "sort must be points, solves, title or category"
+		}
+		filter.Sort = order.field
+		filter.Descending = order.descending
+	}
+
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		filter.Descending = false
+	case "desc":
+		filter.Descending = true
+	default:
+		return filter, "order must be asc or desc"
+	}
+
+	return filter, ""
+}
+
+// GetAllChallenges lists the challenges the player can see, filtered by category,
+// difficulty, tag, solved state and text search, in the requested order
 func (h *ChallengeHandler) GetAllChallenges(c *gin.Context) {
-	challenges, err := h.challengeService.GetAllChallenges()
+	if !isAdmin(c) {
+		if err := h.eventService.CheckChallengeAccess(); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	filter, msg := parseChallengeFilter(c)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	access, err := h.challengeAccess(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	var result []ChallengePublicResponse
-	for _, ch := range challenges {
-		result = append(result, ChallengePublicResponse{
-			ID:            ch.ID.Hex(),
-			Title:         ch.Title,
-			Description:   ch.Description,
-			Category:      ch.Category,
-			Difficulty:    ch.Difficulty,
-			MaxPoints:     ch.MaxPoints,
-			CurrentPoints: ch.CurrentPoints(),
-			SolveCount:    ch.SolveCount,
-			Files:         ch.Files,
-		})
+	if access != nil {
+		filter.ReleasedAt = time.Now()
+	}
+	if filter.Solved != nil {
+		solvedBy := access
+		if solvedBy == nil {
+			// Admins filter by their own team's solves, without the player locking rules
+			userIDStr, _ := c.Get("user_id")
+			userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+			if solvedBy, err = h.challengeService.GetChallengeAccess(userID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		filter.SolvedIDs = solvedBy.SolvedIDs()
+	}
+
+	challenges, err := h.challengeService.FindChallenges(filter, access)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]ChallengePublicResponse, 0, len(challenges))
+	for i := range challenges {
+		if response, visible := newChallengePublicResponse(&challenges[i], access); visible {
+			result = append(result, response)
+		}
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
 func (h *ChallengeHandler) GetChallengeByID(c *gin.Context) {
+	if !isAdmin(c) {
+		if err := h.eventService.CheckChallengeAccess(); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	id := c.Param("id")
 	challenge, err := h.challengeService.GetChallengeByID(id)
 	if err != nil {
@@ -193,22 +626,44 @@ func (h *ChallengeHandler) GetChallengeByID(c *gin.Context) {
 		return
 	}
 
+	access, err := h.challengeAccess(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Return public response (no flag hash)
-	response := ChallengePublicResponse{
-		ID:            challenge.ID.Hex(),
-		Title:         challenge.Title,
-		Description:   challenge.Description,
-		Category:      challenge.Category,
-		Difficulty:    challenge.Difficulty,
-		MaxPoints:     challenge.MaxPoints,
-		CurrentPoints: challenge.CurrentPoints(),
-		SolveCount:    challenge.SolveCount,
-		Files:         challenge.Files,
+	response, visible := newChallengePublicResponse(challenge, access)
+	if !visible {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetDynamicFlags lists every team's derived flag for a dynamic challenge (admin only)
+func (h *ChallengeHandler) GetDynamicFlags(c *gin.Context) {
+	flags, err := h.challengeService.GetDynamicFlags(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, flags)
+}
+
+// GetCheatReports lists submissions of another team's dynamic flag (admin only)
+func (h *ChallengeHandler) GetCheatReports(c *gin.Context) {
+	reports, err := h.challengeService.GetCheatReports()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
 type SubmitFlagRequest struct {
 	Flag string `json:"flag" binding:"required"`
 }
@@ -230,7 +685,22 @@ func (h *ChallengeHandler) SubmitFlag(c *gin.Context) {
 		return
 	}
 
+	if !isAdmin(c) {
+		if err := h.eventService.CheckSubmissionAllowed(); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err := h.challengeService.EnsureOpen(challengeID); err != nil {
+			c.JSON(lockStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	result, err := h.challengeService.SubmitFlag(userID, challengeID, req.Flag)
+	if errors.Is(err, services.ErrChallengeLocked) || errors.Is(err, services.ErrUserBanned) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -251,6 +721,13 @@ func (h *ChallengeHandler) SubmitFlag(c *gin.Context) {
 		if result.TeamName != "" {
 			response["team_name"] = result.TeamName
 		}
+		if result.SolvePosition > 0 {
+			response["solve_position"] = result.SolvePosition
+		}
+		if result.Badge != "" {
+			response["badge"] = result.Badge
+			response["blood_bonus"] = result.BloodBonus
+		}
 	} else {
 		response["message"] = "Flag incorrect"
 	}
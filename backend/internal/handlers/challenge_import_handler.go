@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/services"
+)
+
+// importArchiveFiles is how many maximum-size attachments an import archive may hold
+const importArchiveFiles = 10
+
+type ChallengeImportHandler struct {
+	importService *services.ChallengeImportService
+	fileService   *services.FileService
+}
+
+func NewChallengeImportHandler(importService *services.ChallengeImportService, fileService *services.FileService) *ChallengeImportHandler {
+	return &ChallengeImportHandler{
+		importService: importService,
+		fileService:   fileService,
+	}
+}
+
+// ImportChallenges creates or updates challenges from a zip of challenge.yml directories (admin only)
+func (h *ChallengeImportHandler) ImportChallenges(c *gin.Context) {
+	maxSize := h.fileService.MaxSize() * importArchiveFiles
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1024*1024)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "archive exceeds maximum upload size"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	if header.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "archive exceeds maximum upload size"})
+		return
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer src.Close()
+
+	archive, err := zip.NewReader(src, header.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is not a valid zip archive"})
+		return
+	}
+
+	result, err := h.importService.Import(archive)
+	if err != nil {
+		// Challenges imported before the failure stay imported; report them too
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportChallenges downloads every challenge as a zip of challenge.yml directories (admin only)
+func (h *ChallengeImportHandler) ExportChallenges(c *gin.Context) {
+	// Build the archive first so a failure can still be reported as an error
+	var buf bytes.Buffer
+	if err := h.importService.Export(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=challenges.zip")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventHandler struct {
+	eventService *services.EventService
+}
+
+func NewEventHandler(eventService *services.EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// EventResponse describes the competition schedule; unset times are empty strings
+type EventResponse struct {
+	Status     string `json:"status"`
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+	FreezeTime string `json:"freeze_time"`
+	Paused     bool   `json:"paused"`
+	Frozen     bool   `json:"frozen"`
+	ServerTime string `json:"server_time"`
+}
+
+func formatEventTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func newEventResponse(cfg *models.EventConfig) EventResponse {
+	now := time.Now()
+	return EventResponse{
+		Status:     cfg.Status(now),
+		StartTime:  formatEventTime(cfg.StartTime),
+		EndTime:    formatEventTime(cfg.EndTime),
+		FreezeTime: formatEventTime(cfg.FreezeTime),
+		Paused:     cfg.Paused,
+		Frozen:     cfg.IsFrozen(now),
+		ServerTime: formatEventTime(now),
+	}
+}
+
+// GetEvent returns the competition schedule and its current status
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	cfg, err := h.eventService.GetConfig()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newEventResponse(cfg))
+}
+
+// UpdateEventRequest replaces the competition schedule; omitted times are cleared
+type UpdateEventRequest struct {
+	StartTime  *time.Time `json:"start_time"`
+	EndTime    *time.Time `json:"end_time"`
+	FreezeTime *time.Time `json:"freeze_time"`
+	Paused     bool       `json:"paused"`
+}
+
+// UpdateEvent changes the competition schedule at runtime (admin only)
+func (h *EventHandler) UpdateEvent(c *gin.Context) {
+	var req UpdateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	adminID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+
+	cfg := &models.EventConfig{Paused: req.Paused}
+	if req.StartTime != nil {
+		cfg.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		cfg.EndTime = *req.EndTime
+	}
+	if req.FreezeTime != nil {
+		cfg.FreezeTime = *req.FreezeTime
+	}
+
+	if err := h.eventService.UpdateConfig(cfg, adminID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Event updated successfully",
+		"event":   newEventResponse(cfg),
+	})
+}
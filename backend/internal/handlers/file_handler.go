@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"github.com/go-ctf-platform/backend/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FileHandler struct {
+	fileService      *services.FileService
+	challengeService *services.ChallengeService
+	eventService     *services.EventService
+}
+
+func NewFileHandler(fileService *services.FileService, challengeService *services.ChallengeService, eventService *services.EventService) *FileHandler {
+	return &FileHandler{
+		fileService:      fileService,
+		challengeService: challengeService,
+		eventService:     eventService,
+	}
+}
+
+// UploadFile stores a multipart "file" upload as a challenge attachment (admin only)
+func (h *FileHandler) UploadFile(c *gin.Context) {
+	challengeID := c.Param("id")
+
+	// Reject oversized bodies before they are buffered; allow some room for multipart framing
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.fileService.MaxSize()+1024*1024)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file exceeds maximum upload size"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	if header.Size > h.fileService.MaxSize() {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file exceeds maximum upload size"})
+		return
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer src.Close()
+
+	file, err := h.fileService.UploadFile(challengeID, header.Filename, header.Header.Get("Content-Type"), src, header.Size)
+	if err != nil {
+		c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "File uploaded successfully",
+		"file":    file,
+	})
+}
+
+// DownloadFile streams a challenge attachment to an authenticated user
+func (h *FileHandler) DownloadFile(c *gin.Context) {
+	if !isAdmin(c) {
+		if err := h.eventService.CheckChallengeAccess(); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		userIDStr, _ := c.Get("user_id")
+		userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+		if err := h.challengeService.EnsureUnlocked(userID, c.Param("id")); err != nil {
+			c.JSON(lockStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	file, reader, err := h.fileService.OpenFile(c.Param("id"), c.Param("fileId"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%s", strconv.Quote(file.Name)),
+		"X-Checksum-SHA256":   file.SHA256,
+	})
+}
+
+// DeleteFile removes a challenge attachment (admin only)
+func (h *FileHandler) DeleteFile(c *gin.Context) {
+	if err := h.fileService.DeleteFile(c.Param("id"), c.Param("fileId")); err != nil {
+		c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "File deleted successfully"})
+}
+
+// fileErrorStatus maps a FileService error to a status code
+func fileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrFileChallengeNotFound), errors.Is(err, services.ErrFileNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidFileID):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
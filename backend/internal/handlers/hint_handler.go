@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type HintHandler struct {
+	hintService      *services.HintService
+	challengeService *services.ChallengeService
+	eventService     *services.EventService
+}
+
+func NewHintHandler(hintService *services.HintService, challengeService *services.ChallengeService, eventService *services.EventService) *HintHandler {
+	return &HintHandler{
+		hintService:      hintService,
+		challengeService: challengeService,
+		eventService:     eventService,
+	}
+}
+
+// GetHints returns the hints of a challenge; locked hints have no content
+func (h *HintHandler) GetHints(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+
+	if !isAdmin(c) {
+		if err := h.eventService.CheckChallengeAccess(); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err := h.challengeService.EnsureUnlocked(userID, c.Param("id")); err != nil {
+			c.JSON(lockStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	hints, err := h.hintService.GetHints(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, hints)
+}
+
+// UnlockHint unlocks a hint for the current user's team, deducting its cost
+func (h *HintHandler) UnlockHint(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+
+	if !isAdmin(c) {
+		// Buying a hint changes the score, so it follows the submission schedule
+		if err := h.eventService.CheckSubmissionAllowed(); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err := h.challengeService.EnsureUnlocked(userID, c.Param("id")); err != nil {
+			c.JSON(lockStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if err := h.challengeService.EnsureOpen(c.Param("id")); err != nil {
+			c.JSON(lockStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	hint, err := h.hintService.UnlockHint(userID, c.Param("id"), c.Param("hintId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hint unlocked",
+		"hint":    hint,
+	})
+}
+
+// GetHintUnlocks returns who unlocked which hints of a challenge (admin only)
+func (h *HintHandler) GetHintUnlocks(c *gin.Context) {
+	unlocks, err := h.hintService.GetHintUnlocks(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, unlocks)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/config"
+	"github.com/go-ctf-platform/backend/internal/services"
+)
+
+const (
+	// oauthStateCookie holds the signed state of a login in progress at a provider
+	oauthStateCookie = "oauth_state"
+	// oauthCookiePath limits the state cookie to the OAuth endpoints
+	oauthCookiePath = "/auth/oauth"
+)
+
+type OAuthHandler struct {
+	oauthService *services.OAuthService
+	config       *config.Config
+}
+
+func NewOAuthHandler(oauthService *services.OAuthService, cfg *config.Config) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+		config:       cfg,
+	}
+}
+
+// GetProviders lists the login providers the frontend can offer
+func (h *OAuthHandler) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.oauthService.GetProviders()})
+}
+
+// Login redirects the browser to the provider's login page
+func (h *OAuthHandler) Login(c *gin.Context) {
+	authURL, stateToken, err := h.oauthService.BeginLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		h.redirectError(c, err.Error())
+		return
+	}
+
+	// The provider redirects back with a top-level GET, which still carries the cookie
+	c.SetCookie(oauthStateCookie, stateToken, int(services.OAuthStateTTL.Seconds()), oauthCookiePath, "", false, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback finishes a login when the provider redirects back. On success the auth
+// cookies are set and the browser is sent to the frontend; users with two-factor
+// authentication get the challenge token in the URL fragment, for POST /auth/login/2fa.
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	stateToken, _ := c.Cookie(oauthStateCookie)
+	c.SetCookie(oauthStateCookie, "", -1, oauthCookiePath, "", false, true)
+
+	if providerError := c.Query("error"); providerError != "" {
+		message := "login was cancelled or denied by the provider"
+		if providerError != "access_denied" {
+			message = "login with the provider failed: " + providerError
+		}
+		h.redirectError(c, message)
+		return
+	}
+
+	result, err := h.oauthService.CompleteLogin(
+		c.Request.Context(),
+		c.Param("provider"),
+		c.Query("code"),
+		c.Query("state"),
+		stateToken,
+		clientInfo(c),
+	)
+	if err != nil {
+		h.redirectError(c, err.Error())
+		return
+	}
+
+	if result.TwoFactorRequired {
+		c.Redirect(http.StatusFound, h.frontendURL("/login")+"#two_factor_challenge="+url.QueryEscape(result.ChallengeToken))
+		return
+	}
+
+	setAuthCookies(c, result)
+	c.Redirect(http.StatusFound, h.frontendURL("/challenges"))
+}
+
+// redirectError sends the browser back to the login page with an error to show
+func (h *OAuthHandler) redirectError(c *gin.Context, message string) {
+	c.Redirect(http.StatusFound, h.frontendURL("/login")+"?oauth_error="+url.QueryEscape(message))
+}
+
+func (h *OAuthHandler) frontendURL(path string) string {
+	return strings.TrimRight(h.config.FrontendURL, "/") + path
+}
@@ -13,6 +13,8 @@ type ProfileHandler struct {
 	submissionRepo *repositories.SubmissionRepository
 	challengeRepo  *repositories.ChallengeRepository
 	teamRepo       *repositories.TeamRepository
+	solveRepo      *repositories.SolveRepository
+	awardRepo      *repositories.AwardRepository
 }
 
 func NewProfileHandler(
@@ -20,12 +22,16 @@ func NewProfileHandler(
 	submissionRepo *repositories.SubmissionRepository,
 	challengeRepo *repositories.ChallengeRepository,
 	teamRepo *repositories.TeamRepository,
+	solveRepo *repositories.SolveRepository,
+	awardRepo *repositories.AwardRepository,
 ) *ProfileHandler {
 	return &ProfileHandler{
 		userRepo:       userRepo,
 		submissionRepo: submissionRepo,
 		challengeRepo:  challengeRepo,
 		teamRepo:       teamRepo,
+		solveRepo:      solveRepo,
+		awardRepo:      awardRepo,
 	}
 }
 
@@ -36,9 +42,26 @@ type SolvedChallenge struct {
 	Category      string `json:"category"`
 	Difficulty    string `json:"difficulty"`
 	Points        int    `json:"points"`
+	BloodBonus    int    `json:"blood_bonus,omitempty"`
+	Badge         string `json:"badge,omitempty"`
 	SolvedAt      string `json:"solved_at"`
 }
 
+// Badge is a first, second or third blood earned by the user
+type Badge struct {
+	Type           string `json:"type"`
+	ChallengeID    string `json:"challenge_id"`
+	ChallengeTitle string `json:"challenge_title"`
+	EarnedAt       string `json:"earned_at"`
+}
+
+// ProfileAward is a manual award or penalty given to the user
+type ProfileAward struct {
+	Points    int    `json:"points"`
+	Reason    string `json:"reason"`
+	AwardedAt string `json:"awarded_at"`
+}
+
 // CategoryStats represents solve statistics by category
 type CategoryStats struct {
 	Category    string `json:"category"`
@@ -57,6 +80,8 @@ type UserProfileResponse struct {
 	TotalSubmissions  int64             `json:"total_submissions"`
 	SolvedChallenges  []SolvedChallenge `json:"solved_challenges"`
 	CategoryStats     []CategoryStats   `json:"category_stats"`
+	Badges            []Badge           `json:"badges"`
+	Awards            []ProfileAward    `json:"awards"`
 }
 
 // GetUserProfile returns the public profile of a user by username
@@ -92,8 +117,17 @@ func (h *ProfileHandler) GetUserProfile(c *gin.Context) {
 	// Get total submission count
 	totalSubmissions, _ := h.submissionRepo.GetUserSubmissionCount(user.ID)
 
+	// Map challenge ID to the solve the user recorded, for blood bonuses and badges
+	userSolves := make(map[string]models.Solve)
+	if solves, err := h.solveRepo.GetUserSolves(user.ID); err == nil {
+		for _, solve := range solves {
+			userSolves[solve.ChallengeID.Hex()] = solve
+		}
+	}
+
 	// Build solved challenges list and calculate stats
 	var solvedChallenges []SolvedChallenge
+	badges := []Badge{}
 	categoryStatsMap := make(map[string]*CategoryStats)
 	totalPoints := 0
 	seenChallenges := make(map[string]bool) // To avoid duplicates
@@ -111,7 +145,9 @@ func (h *ProfileHandler) GetUserProfile(c *gin.Context) {
 		}
 
 		points := challenge.CurrentPoints()
-		totalPoints += points
+		solve := userSolves[challengeID]
+		badge := models.BloodBadge(solve.Position)
+		totalPoints += points + solve.Bonus
 
 		solvedChallenges = append(solvedChallenges, SolvedChallenge{
 			ID:         challengeID,
@@ -119,9 +155,20 @@ func (h *ProfileHandler) GetUserProfile(c *gin.Context) {
 			Category:   challenge.Category,
 			Difficulty: challenge.Difficulty,
 			Points:     points,
+			BloodBonus: solve.Bonus,
+			Badge:      badge,
 			SolvedAt:   sub.Timestamp.Format("2006-01-02T15:04:05Z"),
 		})
 
+		if badge != "" {
+			badges = append(badges, Badge{
+				Type:           badge,
+				ChallengeID:    challengeID,
+				ChallengeTitle: challenge.Title,
+				EarnedAt:       solve.SolvedAt.Format("2006-01-02T15:04:05Z"),
+			})
+		}
+
 		// Update category stats
 		if _, exists := categoryStatsMap[challenge.Category]; !exists {
 			categoryStatsMap[challenge.Category] = &CategoryStats{
@@ -132,6 +179,19 @@ func (h *ProfileHandler) GetUserProfile(c *gin.Context) {
 		categoryStatsMap[challenge.Category].TotalPoints += points
 	}
 
+	// Apply manual awards and penalties given to the user
+	awards := []ProfileAward{}
+	if userAwards, err := h.awardRepo.GetUserAwards(user.ID); err == nil {
+		for _, award := range userAwards {
+			totalPoints += award.Points
+			awards = append(awards, ProfileAward{
+				Points:    award.Points,
+				Reason:    award.Reason,
+				AwardedAt: award.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			})
+		}
+	}
+
 	// Convert category stats map to slice
 	var categoryStats []CategoryStats
 	for _, stats := range categoryStatsMap {
@@ -157,6 +217,8 @@ func (h *ProfileHandler) GetUserProfile(c *gin.Context) {
 		TotalSubmissions: totalSubmissions,
 		SolvedChallenges: solvedChallenges,
 		CategoryStats:    categoryStats,
+		Badges:           badges,
+		Awards:           awards,
 	}
 
 	c.JSON(http.StatusOK, profile)
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RescoreHandler struct {
+	rescoreService *services.RescoreService
+}
+
+func NewRescoreHandler(rescoreService *services.RescoreService) *RescoreHandler {
+	return &RescoreHandler{
+		rescoreService: rescoreService,
+	}
+}
+
+// rescoreParams reads the admin ID and the dry_run query flag of a rescore request
+func rescoreParams(c *gin.Context) (primitive.ObjectID, bool, bool) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return primitive.NilObjectID, false, false
+	}
+	adminID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return primitive.NilObjectID, false, false
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+			return primitive.NilObjectID, false, false
+		}
+	}
+	return adminID, dryRun, true
+}
+
+// GetChallengeSolves lists the solves of a challenge in solve order (admin only)
+func (h *RescoreHandler) GetChallengeSolves(c *gin.Context) {
+	solves, err := h.rescoreService.GetChallengeSolves(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, solves)
+}
+
+// RevokeSolve revokes a solve and rescores the event; ?dry_run=true only returns the diff (admin only)
+func (h *RescoreHandler) RevokeSolve(c *gin.Context) {
+	adminID, dryRun, ok := rescoreParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.rescoreService.RevokeSolve(c.Param("id"), adminID, dryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "solve not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ResetChallenge revokes every solve of a challenge and rescores the event; ?dry_run=true only returns the diff (admin only)
+func (h *RescoreHandler) ResetChallenge(c *gin.Context) {
+	adminID, dryRun, ok := rescoreParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.rescoreService.ResetChallenge(c.Param("id"), adminID, dryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "challenge not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Recompute rebuilds solve counts, solve positions and team scores from the submissions;
+// ?dry_run=true only returns the diff (admin only)
+func (h *RescoreHandler) Recompute(c *gin.Context) {
+	adminID, dryRun, ok := rescoreParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.rescoreService.Recompute(adminID, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
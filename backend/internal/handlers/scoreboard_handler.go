@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-ctf-platform/backend/internal/services"
@@ -38,3 +39,57 @@ func (h *ScoreboardHandler) GetTeamScoreboard(c *gin.Context) {
 		"teams": scores,
 	})
 }
+
+// GetLiveScoreboard returns the user scoreboard ignoring the freeze (admin only)
+func (h *ScoreboardHandler) GetLiveScoreboard(c *gin.Context) {
+	scores, err := h.scoreboardService.GetLiveScoreboard()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scores)
+}
+
+// GetLiveTeamScoreboard returns the team scoreboard ignoring the freeze (admin only)
+func (h *ScoreboardHandler) GetLiveTeamScoreboard(c *gin.Context) {
+	scores, err := h.scoreboardService.GetLiveTeamScoreboard()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"teams": scores,
+	})
+}
+
+// GetScoreHistory returns the score-over-time series of the top teams (?top=10 by default)
+func (h *ScoreboardHandler) GetScoreHistory(c *gin.Context) {
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil || top < 1 || top > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "top must be between 1 and 100"})
+		return
+	}
+
+	history, err := h.scoreboardService.GetTeamScoreHistory(top)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"teams": history,
+	})
+}
+
+// GetCTFtimeScoreboard returns the team standings in the CTFtime feed format
+func (h *ScoreboardHandler) GetCTFtimeScoreboard(c *gin.Context) {
+	scoreboard, err := h.scoreboardService.GetCTFtimeScoreboard()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scoreboard)
+}
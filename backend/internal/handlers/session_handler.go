@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// SessionResponse is an active session; Current marks the one making the request
+type SessionResponse struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+// GetSessions lists the current user's active sessions
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	sessions, err := h.sessionService.GetUserSessions(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := c.GetString("session_id")
+	response := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = SessionResponse{Session: session, Current: session.JTI == current}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeSession signs out one of the current user's sessions
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.sessionService.RevokeUserSession(userID.(string), c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// RevokeOtherSessions signs out every session of the current user but this one
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeAllSessions(id, c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Other sessions revoked",
+		"revoked": revoked,
+	})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/realtime"
+)
+
+// streamHeartbeat keeps idle connections from being closed by proxies
+const streamHeartbeat = 30 * time.Second
+
+type StreamHandler struct {
+	broker realtime.Broker
+}
+
+func NewStreamHandler(broker realtime.Broker) *StreamHandler {
+	return &StreamHandler{
+		broker: broker,
+	}
+}
+
+// Stream sends solves, first bloods, scoreboard deltas and notifications as Server-Sent Events
+func (h *StreamHandler) Stream(c *gin.Context) {
+	events, unsubscribe := h.broker.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable Nginx response buffering
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+			return true
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SubmissionHandler struct {
+	submissionService *services.SubmissionService
+}
+
+func NewSubmissionHandler(submissionService *services.SubmissionService) *SubmissionHandler {
+	return &SubmissionHandler{
+		submissionService: submissionService,
+	}
+}
+
+// parseSubmissionFilter reads the submissions browser filters from the query string
+func parseSubmissionFilter(c *gin.Context) (repositories.SubmissionFilter, string) {
+	var filter repositories.SubmissionFilter
+
+	ids := map[string]*primitive.ObjectID{
+		"user_id":      &filter.UserID,
+		"team_id":      &filter.TeamID,
+		"challenge_id": &filter.ChallengeID,
+	}
+	for param, target := range ids {
+		if value := c.Query(param); value != "" {
+			id, err := primitive.ObjectIDFromHex(value)
+			if err != nil {
+				return filter, "invalid " + param
+			}
+			*target = id
+		}
+	}
+
+	if value := c.Query("correct"); value != "" {
+		correct, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, "correct must be true or false"
+		}
+		filter.IsCorrect = &correct
+	}
+
+	times := map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+	for param, target := range times {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, param + " must be an RFC 3339 timestamp"
+			}
+			*target = t
+		}
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, "order must be asc or desc"
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > services.MaxSubmissionPageSize {
+			return filter, "limit must be between 1 and " + strconv.Itoa(services.MaxSubmissionPageSize)
+		}
+		filter.Limit = limit
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := services.DecodeSubmissionCursor(value)
+		if err != nil {
+			return filter, err.Error()
+		}
+		filter.After = cursor
+	}
+
+	return filter, ""
+}
+
+// GetSubmissions lists submissions with filters and cursor pagination (admin only).
+// Query: user_id, team_id, challenge_id, correct, from, to, order (asc|desc), limit, cursor
+func (h *SubmissionHandler) GetSubmissions(c *gin.Context) {
+	filter, errMsg := parseSubmissionFilter(c)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	page, err := h.submissionService.ListSubmissions(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
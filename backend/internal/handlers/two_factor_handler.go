@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type SecuritySettingsRequest struct {
+	RequireAdminTwoFactor bool `json:"require_admin_two_factor"`
+}
+
+// twoFactorStatus maps code verification errors to HTTP status codes
+func twoFactorStatus(err error) int {
+	if errors.Is(err, services.ErrTwoFactorLocked) {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusBadRequest
+}
+
+// GetStatus returns the current user's two-factor setup
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	status, err := h.twoFactorService.GetStatus(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// BeginEnrollment generates a TOTP secret and its provisioning URI and QR code
+func (h *TwoFactorHandler) BeginEnrollment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	enrollment, err := h.twoFactorService.BeginEnrollment(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmEnrollment enables two-factor authentication and returns the recovery codes
+func (h *TwoFactorHandler) ConfirmEnrollment(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	codes, err := h.twoFactorService.ConfirmEnrollment(userID.(string), req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled. Store your recovery codes somewhere safe; they are only shown once.",
+		"recovery_codes": codes,
+	})
+}
+
+// Disable turns two-factor authentication off
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.twoFactorService.Disable(userID.(string), req.Password, req.Code); err != nil {
+		c.JSON(twoFactorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(userID.(string), req.Code)
+	if err != nil {
+		c.JSON(twoFactorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// GetSecuritySettings returns the account security policies (admin only)
+func (h *TwoFactorHandler) GetSecuritySettings(c *gin.Context) {
+	settings, err := h.twoFactorService.GetSecuritySettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSecuritySettings changes the account security policies (admin only)
+func (h *TwoFactorHandler) UpdateSecuritySettings(c *gin.Context) {
+	var req SecuritySettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	adminID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+
+	settings, err := h.twoFactorService.SetAdminRequirement(req.RequireAdminTwoFactor, adminID, c.GetBool("two_factor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Security settings updated",
+		"settings": settings,
+	})
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -10,8 +11,19 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+// SessionValidator checks that the server-side session of a token is still active
+type SessionValidator interface {
+	ValidateSession(jti, userID string) error
+}
+
+// AuthMiddleware authenticates sessions, and personal API tokens when tokens is not nil
+func AuthMiddleware(cfg *config.Config, sessions SessionValidator, tokens TokenAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiToken, ok := apiTokenFromHeader(c); ok {
+			authenticateAPIToken(c, tokens, apiToken)
+			return
+		}
+
 		// Try to get token from cookie first
 		tokenString, err := c.Cookie("auth_token")
 		
@@ -40,6 +52,12 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return []byte(cfg.JWTSecret), nil
 		})
 
+		// Access tokens are short-lived; clients renew them at /auth/refresh
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired", "token_expired": true})
+			c.Abort()
+			return
+		}
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
@@ -53,16 +71,32 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		// Logged out, revoked and pre-session tokens have no active session
+		userID, _ := claims["user_id"].(string)
+		jti, _ := claims["jti"].(string)
+		if err := sessions.ValidateSession(jti, userID); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims["user_id"])
 		c.Set("username", claims["username"])
 		c.Set("email", claims["email"])
 		c.Set("role", claims["role"])
+		c.Set("two_factor", claims["two_factor"] == true)
+		c.Set("session_id", jti)
 
 		c.Next()
 	}
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+// TwoFactorPolicy reports whether admin sessions must have signed in with 2FA
+type TwoFactorPolicy interface {
+	AdminsRequireTwoFactor() bool
+}
+
+func AdminMiddleware(policy TwoFactorPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists || role != "admin" {
@@ -70,6 +104,14 @@ func AdminMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-		c.Next()
+		if c.GetBool("two_factor") || !policy.AdminsRequireTwoFactor() {
+			c.Next()
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":               "Two-factor authentication required for admin access",
+			"two_factor_required": true,
+		})
+		c.Abort()
 	}
 }
@@ -8,7 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// RateLimiter tracks submission attempts per user per challenge
+// RateLimiter tracks attempts per key within a time window
 type RateLimiter struct {
 	attempts map[string][]time.Time
 	mu       sync.RWMutex
@@ -19,6 +19,11 @@ var flagSubmitLimiter = &RateLimiter{
 	attempts: make(map[string][]time.Time),
 }
 
+// ipLimiter tracks attempts per client IP per endpoint
+var ipLimiter = &RateLimiter{
+	attempts: make(map[string][]time.Time),
+}
+
 // RateLimitMiddleware creates a middleware that limits requests per user per challenge
 // maxAttempts: maximum number of attempts allowed within the time window
 // window: time duration for the rate limit window
@@ -40,29 +45,8 @@ func RateLimitMiddleware(maxAttempts int, window time.Duration) gin.HandlerFunc
 		// Create unique key for user+challenge combination
 		key := userID.(string) + ":" + challengeID
 
-		flagSubmitLimiter.mu.Lock()
-		defer flagSubmitLimiter.mu.Unlock()
-
-		now := time.Now()
-
-		// Clean up old attempts outside the window
-		if attempts, ok := flagSubmitLimiter.attempts[key]; ok {
-			validAttempts := make([]time.Time, 0)
-			for _, t := range attempts {
-				if now.Sub(t) <= window {
-					validAttempts = append(validAttempts, t)
-				}
-			}
-			flagSubmitLimiter.attempts[key] = validAttempts
-		}
-
-		// Check if user has exceeded rate limit
-		attempts := flagSubmitLimiter.attempts[key]
-		if len(attempts) >= maxAttempts {
-			// Calculate time until next attempt is allowed
-			oldestAttempt := attempts[0]
-			waitTime := window - now.Sub(oldestAttempt)
-			
+		remaining, waitTime := flagSubmitLimiter.record(key, maxAttempts, window)
+		if remaining < 0 {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "Too many attempts. Please wait before trying again.",
 				"retry_after": int(waitTime.Seconds()),
@@ -72,11 +56,7 @@ func RateLimitMiddleware(maxAttempts int, window time.Duration) gin.HandlerFunc
 			return
 		}
 
-		// Record this attempt
-		flagSubmitLimiter.attempts[key] = append(flagSubmitLimiter.attempts[key], now)
-
 		// Add rate limit headers
-		remaining := maxAttempts - len(flagSubmitLimiter.attempts[key])
 		c.Header("X-RateLimit-Limit", string(rune(maxAttempts)))
 		c.Header("X-RateLimit-Remaining", string(rune(remaining)))
 
@@ -84,6 +64,54 @@ func RateLimitMiddleware(maxAttempts int, window time.Duration) gin.HandlerFunc
 	}
 }
 
+// IPRateLimitMiddleware limits requests per client IP to an endpoint, for routes
+// used before logging in
+func IPRateLimitMiddleware(maxAttempts int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.ClientIP() + ":" + c.FullPath()
+
+		remaining, waitTime := ipLimiter.record(key, maxAttempts, window)
+		if remaining < 0 {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Too many attempts. Please wait before trying again.",
+				"retry_after": int(waitTime.Seconds()),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// record counts an attempt for the key and returns how many remain in the window.
+// Over the limit the attempt is not counted, and it returns -1 and the time until
+// the next attempt is allowed.
+func (l *RateLimiter) record(key string, maxAttempts int, window time.Duration) (int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Clean up old attempts outside the window
+	validAttempts := make([]time.Time, 0)
+	for _, t := range l.attempts[key] {
+		if now.Sub(t) <= window {
+			validAttempts = append(validAttempts, t)
+		}
+	}
+
+	// Check if the limit has been exceeded
+	if len(validAttempts) >= maxAttempts {
+		l.attempts[key] = validAttempts
+		return -1, window - now.Sub(validAttempts[0])
+	}
+
+	// Record this attempt
+	l.attempts[key] = append(validAttempts, now)
+	return maxAttempts - len(l.attempts[key]), 0
+}
+
 // CleanupExpiredAttempts periodically cleans up expired rate limit entries
 // Call this in a goroutine during application startup
 func CleanupExpiredAttempts(window time.Duration, interval time.Duration) {
@@ -91,21 +119,28 @@ func CleanupExpiredAttempts(window time.Duration, interval time.Duration) {
 	defer ticker.Stop()
 
 	for range ticker.C {
-		flagSubmitLimiter.mu.Lock()
-		now := time.Now()
-		for key, attempts := range flagSubmitLimiter.attempts {
-			validAttempts := make([]time.Time, 0)
-			for _, t := range attempts {
-				if now.Sub(t) <= window {
-					validAttempts = append(validAttempts, t)
-				}
-			}
-			if len(validAttempts) == 0 {
-				delete(flagSubmitLimiter.attempts, key)
-			} else {
-				flagSubmitLimiter.attempts[key] = validAttempts
+		flagSubmitLimiter.cleanup(window)
+		ipLimiter.cleanup(window)
+	}
+}
+
+// cleanup drops the attempts outside the window, and the keys left without any
+func (l *RateLimiter) cleanup(window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for key, attempts := range l.attempts {
+		validAttempts := make([]time.Time, 0)
+		for _, t := range attempts {
+			if now.Sub(t) <= window {
+				validAttempts = append(validAttempts, t)
 			}
 		}
-		flagSubmitLimiter.mu.Unlock()
+		if len(validAttempts) == 0 {
+			delete(l.attempts, key)
+		} else {
+			l.attempts[key] = validAttempts
+		}
 	}
 }
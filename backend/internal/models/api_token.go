@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Personal API token scopes
+const (
+	ScopeChallengesRead = "challenges:read" // List and view challenges, hints and attachments
+	ScopeFlagsSubmit    = "flags:submit"    // Submit flags
+	ScopeTeamRead       = "team:read"       // View the user's team and its awards
+)
+
+// APITokenScopes lists every scope a personal API token can be granted
+var APITokenScopes = []string{ScopeChallengesRead, ScopeFlagsSubmit, ScopeTeamRead}
+
+// IsValidScope reports whether scope is a known API token scope
+func IsValidScope(scope string) bool {
+	for _, s := range APITokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIToken is a personal access token a user creates for scripts. Only its SHA-256
+// hash is stored; the token itself is shown once, when it is created.
+type APIToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"` // First characters of the token, to tell tokens apart
+	Hash       string             `bson:"hash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes"`
+	ExpiresAt  time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // Zero means it never expires
+	LastUsedAt time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	LastUsedIP string             `bson:"last_used_ip,omitempty" json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Award is a manual point adjustment made by an admin: a bonus when Points is
+// positive, a penalty when negative. It applies to a team, a user, or a user
+// and the team they belonged to when it was made.
+type Award struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TeamID    primitive.ObjectID `bson:"team_id,omitempty" json:"team_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Points    int                `bson:"points" json:"points"` // Signed adjustment
+	Reason    string             `bson:"reason" json:"reason"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"` // Admin who made the adjustment
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
@@ -1,48 +1,191 @@
 package models
 
 import (
-	"math"
+	"strings"
+	"time"
+	"unicode"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Challenge struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Title       string             `bson:"title" json:"title"`
-	Description string             `bson:"description" json:"description"`
-	Category    string             `bson:"category" json:"category"`
-	Difficulty  string             `bson:"difficulty" json:"difficulty"` // easy, medium, hard
-	MaxPoints   int                `bson:"max_points" json:"max_points"` // Maximum/initial points
-	MinPoints   int                `bson:"min_points" json:"min_points"` // Minimum floor points
-	Decay       int                `bson:"decay" json:"decay"`           // Decay factor (solves to reach midpoint)
-	SolveCount  int                `bson:"solve_count" json:"solve_count"`
-	FlagHash    string             `bson:"flag_hash" json:"-"` // SHA-256 hashed flag (hidden from API)
-	Files       []string           `bson:"files" json:"files"`
-}
-
-// CurrentPoints calculates dynamic points based on solve count using CTFd formula
-// Formula: value = ((min - max) / decay^2) * solves^2 + max
-// This creates a smooth curve where points decrease as more teams solve
-func (c *Challenge) CurrentPoints() int {
-	if c.SolveCount <= 0 {
-		return c.MaxPoints
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Slug          string               `bson:"slug,omitempty" json:"slug,omitempty"` // Stable key for challenge.yml import/export
+	Title         string               `bson:"title" json:"title"`
+	Description   string               `bson:"description" json:"description"`
+	Category      string               `bson:"category" json:"category"`
+	Tags          []string             `bson:"tags,omitempty" json:"tags,omitempty"`
+	Difficulty    string               `bson:"difficulty" json:"difficulty"`     // easy, medium, hard
+	MaxPoints     int                  `bson:"max_points" json:"max_points"`     // Maximum/initial points
+	MinPoints     int                  `bson:"min_points" json:"min_points"`     // Minimum floor points
+	Decay         int                  `bson:"decay" json:"decay"`               // Decay factor (solves to reach midpoint)
+	Scoring       string               `bson:"scoring,omitempty" json:"scoring"` // Scoring function: static, linear, logarithmic or quadratic (default)
+	SolveCount    int                  `bson:"solve_count" json:"solve_count"`
+	FlagHash      string               `bson:"flag_hash" json:"-"`                       // Legacy single SHA-256 hashed flag (hidden from API), superseded by Flags
+	Flags         []Flag               `bson:"flags,omitempty" json:"-"`                 // Accepted flags, tried in order (hidden from API)
+	FlagMode      string               `bson:"flag_mode,omitempty" json:"flag_mode"`     // static (default) or dynamic
+	FlagSecret    string               `bson:"flag_secret,omitempty" json:"-"`           // HMAC secret for dynamic flags (hidden from API)
+	FlagFormat    string               `bson:"flag_format,omitempty" json:"flag_format"` // Wrapper for dynamic flags, e.g. "flag{%s}"
+	Files         []string             `bson:"files" json:"files"`
+	Hints         []Hint               `bson:"hints,omitempty" json:"hints,omitempty"`
+	Attachments   []ChallengeFile      `bson:"attachments,omitempty" json:"attachments,omitempty"`       // Files uploaded to our storage backend
+	Prerequisites []primitive.ObjectID `bson:"prerequisites,omitempty" json:"prerequisites,omitempty"`   // Challenges that must be solved first
+	MinTeamScore  int                  `bson:"min_team_score,omitempty" json:"min_team_score,omitempty"` // Score needed before it unlocks
+	HideLocked    bool                 `bson:"hide_locked,omitempty" json:"hide_locked,omitempty"`       // Hide entirely while locked instead of showing it redacted
+	BloodBonuses  []int                `bson:"blood_bonuses,omitempty" json:"blood_bonuses,omitempty"`   // Extra points for the first, second and third solver
+	Visibility    string               `bson:"visibility,omitempty" json:"visibility"`                   // draft, hidden, visible (default) or archived
+	ReleaseAt     time.Time            `bson:"release_at,omitempty" json:"release_at,omitempty"`         // Visible challenges stay hidden from players until then
+}
+
+// Visibility states; an empty Visibility is treated as visible
+const (
+	VisibilityDraft    = "draft"    // Being written; only admins can see it
+	VisibilityHidden   = "hidden"   // Pulled from the board; only admins can see it, existing solves still count
+	VisibilityVisible  = "visible"  // Shown to players once ReleaseAt, if set, has passed
+	VisibilityArchived = "archived" // Still shown to players, but no longer accepts flags or hint unlocks
+)
+
+// IsValidVisibility reports whether v is a known visibility state
+func IsValidVisibility(v string) bool {
+	switch v {
+	case VisibilityDraft, VisibilityHidden, VisibilityVisible, VisibilityArchived:
+		return true
+	}
+	return false
+}
+
+// VisibilityState returns the challenge's visibility, defaulting to visible
+func (c *Challenge) VisibilityState() string {
+	if c.Visibility == "" {
+		return VisibilityVisible
+	}
+	return c.Visibility
+}
+
+// IsReleased reports whether players can see the challenge at the given time
+func (c *Challenge) IsReleased(now time.Time) bool {
+	switch c.VisibilityState() {
+	case VisibilityVisible:
+		return c.ReleaseAt.IsZero() || !now.Before(c.ReleaseAt)
+	case VisibilityArchived:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether players can submit flags and unlock hints at the given time
+func (c *Challenge) IsOpen(now time.Time) bool {
+	return c.VisibilityState() == VisibilityVisible && c.IsReleased(now)
+}
+
+// MaxBloodBonuses is how many solvers (first, second, third blood) can earn a bonus
+const MaxBloodBonuses = 3
+
+// BloodBonus returns the bonus for the solver at the given 1-based position
+func (c *Challenge) BloodBonus(position int) int {
+	if position < 1 || position > len(c.BloodBonuses) {
+		return 0
+	}
+	return c.BloodBonuses[position-1]
+}
+
+// Flag modes
+const (
+	FlagModeStatic  = "static"  // One flag shared by every team, stored as a hash
+	FlagModeDynamic = "dynamic" // Per-team flag derived from FlagSecret and the team ID
+)
+
+// IsDynamicFlag reports whether each team gets its own derived flag
+func (c *Challenge) IsDynamicFlag() bool {
+	return c.FlagMode == FlagModeDynamic
+}
+
+// StaticFlags returns the accepted static-mode flags, including a legacy FlagHash
+func (c *Challenge) StaticFlags() []Flag {
+	if len(c.Flags) > 0 {
+		return c.Flags
+	}
+	if c.FlagHash != "" {
+		return []Flag{{Type: FlagTypeStatic, Hash: c.FlagHash}}
+	}
+	return nil
+}
+
+// HasUnlockRequirements reports whether the challenge has prerequisites or a minimum score
+func (c *Challenge) HasUnlockRequirements() bool {
+	return len(c.Prerequisites) > 0 || c.MinTeamScore > 0
+}
+
+// HiddenWhileLocked reports whether players who have not unlocked the challenge
+// must not see it at all, not even its title
+func (c *Challenge) HiddenWhileLocked() bool {
+	return c.HideLocked && c.HasUnlockRequirements()
+}
+
+// Slugify turns a title into a slug: lowercase letters and digits separated by single dashes
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// ChallengeFile is an attachment stored through the storage backend
+type ChallengeFile struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Key         string             `bson:"key" json:"-"` // Storage key (hidden from API)
+	Size        int64              `bson:"size" json:"size"`
+	SHA256      string             `bson:"sha256" json:"sha256"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	UploadedAt  time.Time          `bson:"uploaded_at" json:"uploaded_at"`
+}
+
+// FindAttachment returns the attachment with the given ID, or nil if it does not exist
+func (c *Challenge) FindAttachment(fileID primitive.ObjectID) *ChallengeFile {
+	for i := range c.Attachments {
+		if c.Attachments[i].ID == fileID {
+			return &c.Attachments[i]
+		}
 	}
+	return nil
+}
 
-	if c.Decay <= 0 {
-		c.Decay = 10 // Default decay
+// FindHint returns the hint with the given ID, or nil if it does not exist
+func (c *Challenge) FindHint(hintID primitive.ObjectID) *Hint {
+	for i := range c.Hints {
+		if c.Hints[i].ID == hintID {
+			return &c.Hints[i]
+		}
 	}
+	return nil
+}
+
+// CurrentPoints calculates the challenge's value from its solve count using its scoring function
+func (c *Challenge) CurrentPoints() int {
+	return c.PointsAt(c.SolveCount)
+}
+
+// PointsAt returns what the challenge is worth once it has the given number of solves
+func (c *Challenge) PointsAt(solves int) int {
+	return ScoringFunctionFor(c.Scoring).Points(c, solves)
+}
 
-	// CTFd standard formula
-	decaySquared := float64(c.Decay * c.Decay)
-	solvesSquared := float64(c.SolveCount * c.SolveCount)
-	
-	value := ((float64(c.MinPoints) - float64(c.MaxPoints)) / decaySquared) * solvesSquared + float64(c.MaxPoints)
-	
-	// Ensure points don't go below minimum
-	points := int(math.Round(value))
-	if points < c.MinPoints {
-		return c.MinPoints
+// PointsPreview returns the challenge's value after each of the next n solves
+func (c *Challenge) PointsPreview(n int) []int {
+	preview := make([]int, 0, n)
+	for i := 1; i <= n; i++ {
+		preview = append(preview, c.PointsAt(c.SolveCount+i))
 	}
-	
-	return points
+	return preview
 }
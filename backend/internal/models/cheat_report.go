@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CheatReport records a submission of another team's dynamic flag.
+// Both the submitting team and the team the flag was derived for are flagged.
+type CheatReport struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ChallengeID     primitive.ObjectID `bson:"challenge_id" json:"challenge_id"`
+	SubmitterUserID primitive.ObjectID `bson:"submitter_user_id" json:"submitter_user_id"`
+	SubmitterTeamID primitive.ObjectID `bson:"submitter_team_id,omitempty" json:"submitter_team_id,omitempty"`
+	SourceOwnerID   primitive.ObjectID `bson:"source_owner_id" json:"source_owner_id"` // Team (or teamless user) the flag belongs to
+	SourceIsTeam    bool               `bson:"source_is_team" json:"source_is_team"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+}
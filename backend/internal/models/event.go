@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventConfigID is the fixed document ID of the single event configuration
+const EventConfigID = "event"
+
+// Event statuses
+const (
+	EventStatusNotStarted = "not_started"
+	EventStatusRunning    = "running"
+	EventStatusPaused     = "paused"
+	EventStatusEnded      = "ended"
+)
+
+// EventConfig holds the competition schedule; zero times mean "not set"
+type EventConfig struct {
+	ID         string             `bson:"_id" json:"-"`
+	StartTime  time.Time          `bson:"start_time,omitempty" json:"start_time,omitempty"`
+	EndTime    time.Time          `bson:"end_time,omitempty" json:"end_time,omitempty"`
+	FreezeTime time.Time          `bson:"freeze_time,omitempty" json:"freeze_time,omitempty"` // Public scoreboard stops updating at this time
+	Paused     bool               `bson:"paused" json:"paused"`
+	UpdatedBy  primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Status returns the state of the competition at the given time
+func (e *EventConfig) Status(now time.Time) string {
+	if !e.StartTime.IsZero() && now.Before(e.StartTime) {
+		return EventStatusNotStarted
+	}
+	if !e.EndTime.IsZero() && !now.Before(e.EndTime) {
+		return EventStatusEnded
+	}
+	if e.Paused {
+		return EventStatusPaused
+	}
+	return EventStatusRunning
+}
+
+// IsFrozen reports whether the public scoreboard is frozen at the given time
+func (e *EventConfig) IsFrozen(now time.Time) bool {
+	return !e.FreezeTime.IsZero() && !now.Before(e.FreezeTime)
+}
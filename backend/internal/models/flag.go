@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/go-ctf-platform/backend/internal/utils"
+)
+
+// Flag types
+const (
+	FlagTypeStatic          = "static"           // Exact match, stored as a SHA-256 hash
+	FlagTypeCaseInsensitive = "case_insensitive" // Match ignoring case, stored as a hash of the lowercased flag
+	FlagTypeRegex           = "regex"            // Full match against a pattern, stored in plaintext (admin only)
+)
+
+// ErrInvalidFlagType is returned when a flag type is not supported
+var ErrInvalidFlagType = errors.New("invalid flag type")
+
+// Flag is one accepted answer for a static-mode challenge
+type Flag struct {
+	Type    string `bson:"type" json:"type"`
+	Hash    string `bson:"hash,omitempty" json:"-"`    // SHA-256 hash for static and case-insensitive flags
+	Pattern string `bson:"pattern,omitempty" json:"-"` // Plaintext regex, only exposed through admin views
+}
+
+// NewFlag builds a flag of the given type from its plaintext value
+func NewFlag(flagType, value string) (Flag, error) {
+	switch flagType {
+	case "", FlagTypeStatic:
+		return Flag{Type: FlagTypeStatic, Hash: utils.HashFlag(value)}, nil
+	case FlagTypeCaseInsensitive:
+		return Flag{Type: FlagTypeCaseInsensitive, Hash: utils.HashFlag(strings.ToLower(value))}, nil
+	case FlagTypeRegex:
+		if _, err := regexp.Compile(anchorPattern(value)); err != nil {
+			return Flag{}, err
+		}
+		return Flag{Type: FlagTypeRegex, Pattern: value}, nil
+	default:
+		return Flag{}, ErrInvalidFlagType
+	}
+}
+
+// anchorPattern makes a regex flag match the whole submission
+func anchorPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// Matches reports whether a submitted flag satisfies this flag
+func (f *Flag) Matches(submitted string) bool {
+	switch f.Type {
+	case FlagTypeStatic:
+		return utils.VerifyFlag(submitted, f.Hash)
+	case FlagTypeCaseInsensitive:
+		return utils.VerifyFlag(strings.ToLower(submitted), f.Hash)
+	case FlagTypeRegex:
+		re, err := regexp.Compile(anchorPattern(f.Pattern))
+		if err != nil {
+			return false
+		}
+		return re.MatchString(submitted)
+	default:
+		return false
+	}
+}
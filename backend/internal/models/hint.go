@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Hint is an ordered, purchasable hint attached to a challenge
+type Hint struct {
+	ID      primitive.ObjectID `bson:"_id" json:"id"`
+	Content string             `bson:"content" json:"content"`
+	Cost    int                `bson:"cost" json:"cost"`   // Points deducted when unlocked
+	Order   int                `bson:"order" json:"order"` // Hints must be unlocked in ascending order
+}
+
+// HintUnlock records a team (or teamless user) unlocking a hint
+type HintUnlock struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	HintID      primitive.ObjectID `bson:"hint_id" json:"hint_id"`
+	ChallengeID primitive.ObjectID `bson:"challenge_id" json:"challenge_id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TeamID      primitive.ObjectID `bson:"team_id,omitempty" json:"team_id,omitempty"`
+	OwnerID     primitive.ObjectID `bson:"owner_id,omitempty" json:"-"` // The team, or the user without a team; unique per hint
+	Cost        int                `bson:"cost" json:"cost"`            // Cost at the time of unlock
+	UnlockedAt  time.Time          `bson:"unlocked_at" json:"unlocked_at"`
+}
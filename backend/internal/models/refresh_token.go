@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is one link in a session's refresh token chain. Tokens are stored
+// as SHA-256 hashes and rotated on every use; a used token is kept until it expires
+// so presenting it again can be detected as reuse.
+type RefreshToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Hash       string             `bson:"hash" json:"-"`
+	SessionJTI string             `bson:"session_jti" json:"-"` // The session (token family) it belongs to
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	UsedAt     time.Time          `bson:"used_at,omitempty" json:"used_at,omitempty"` // Set when rotated
+}
@@ -0,0 +1,97 @@
+package models
+
+import "math"
+
+// Scoring functions
+const (
+	ScoringStatic      = "static"      // Always worth MaxPoints
+	ScoringLinear      = "linear"      // Loses the same amount per solve until MinPoints
+	ScoringLogarithmic = "logarithmic" // Drops quickly at first, then flattens out
+	ScoringQuadratic   = "quadratic"   // CTFd formula (default)
+)
+
+// ScoringFunction computes a challenge's value once it has been solved a number of times.
+// Decay is the number of solves after which the value reaches MinPoints.
+type ScoringFunction interface {
+	Points(c *Challenge, solves int) int
+}
+
+var scoringFunctions = map[string]ScoringFunction{
+	ScoringStatic:      staticScoring{},
+	ScoringLinear:      linearScoring{},
+	ScoringLogarithmic: logarithmicScoring{},
+	ScoringQuadratic:   quadraticScoring{},
+}
+
+// IsValidScoringFunction reports whether name is a known scoring function
+func IsValidScoringFunction(name string) bool {
+	_, ok := scoringFunctions[name]
+	return ok
+}
+
+// ScoringFunctionFor returns the named scoring function, defaulting to quadratic
+func ScoringFunctionFor(name string) ScoringFunction {
+	if fn, ok := scoringFunctions[name]; ok {
+		return fn
+	}
+	return quadraticScoring{}
+}
+
+// decayOf returns the challenge decay, or the default of 10 solves
+func decayOf(c *Challenge) int {
+	if c.Decay <= 0 {
+		return 10
+	}
+	return c.Decay
+}
+
+// clampPoints rounds a computed value and keeps it from going below MinPoints
+func clampPoints(c *Challenge, value float64) int {
+	points := int(math.Round(value))
+	if points < c.MinPoints {
+		return c.MinPoints
+	}
+	return points
+}
+
+type staticScoring struct{}
+
+func (staticScoring) Points(c *Challenge, solves int) int {
+	return c.MaxPoints
+}
+
+type linearScoring struct{}
+
+// Points: value = max - (max - min) * solves / decay
+func (linearScoring) Points(c *Challenge, solves int) int {
+	if solves <= 0 {
+		return c.MaxPoints
+	}
+	step := float64(c.MaxPoints-c.MinPoints) / float64(decayOf(c))
+	return clampPoints(c, float64(c.MaxPoints)-step*float64(solves))
+}
+
+type logarithmicScoring struct{}
+
+// Points: value = max - (max - min) * ln(1 + solves) / ln(1 + decay)
+func (logarithmicScoring) Points(c *Challenge, solves int) int {
+	if solves <= 0 {
+		return c.MaxPoints
+	}
+	ratio := math.Log1p(float64(solves)) / math.Log1p(float64(decayOf(c)))
+	return clampPoints(c, float64(c.MaxPoints)-float64(c.MaxPoints-c.MinPoints)*ratio)
+}
+
+type quadraticScoring struct{}
+
+// Points uses the CTFd formula: value = ((min - max) / decay^2) * solves^2 + max
+func (quadraticScoring) Points(c *Challenge, solves int) int {
+	if solves <= 0 {
+		return c.MaxPoints
+	}
+	decay := decayOf(c)
+	decaySquared := float64(decay * decay)
+	solvesSquared := float64(solves * solves)
+	value := ((float64(c.MinPoints)-float64(c.MaxPoints))/decaySquared)*solvesSquared + float64(c.MaxPoints)
+	return clampPoints(c, value)
+}
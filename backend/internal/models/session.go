@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session is a signed-in device. Session tokens carry its JTI; a token whose
+// session no longer exists is rejected, which is how logout and revocation work.
+type Session struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	JTI        string             `bson:"jti" json:"-"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TwoFactor  bool               `bson:"two_factor" json:"two_factor"` // Signed in with a second factor
+	IP         string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	LastSeenAt time.Time          `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"` // Expired sessions are removed by a TTL index
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SecuritySettingsID is the fixed document ID of the single security settings document
+const SecuritySettingsID = "security"
+
+// SecuritySettings holds the account security policies admins change at runtime
+type SecuritySettings struct {
+	ID                    string             `bson:"_id" json:"-"`
+	RequireAdminTwoFactor bool               `bson:"require_admin_two_factor" json:"require_admin_two_factor"` // Admin routes need a session signed in with 2FA
+	UpdatedBy             primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt             time.Time          `bson:"updated_at" json:"updated_at"`
+}
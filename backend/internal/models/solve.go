@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Solve records that a team (or teamless user) solved a challenge.
+// The unique (challenge_id, owner_id) index guarantees a solve is only counted once.
+type Solve struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ChallengeID primitive.ObjectID `bson:"challenge_id" json:"challenge_id"`
+	OwnerID     primitive.ObjectID `bson:"owner_id" json:"owner_id"` // Team ID, or user ID when not in a team
+	TeamID      primitive.ObjectID `bson:"team_id,omitempty" json:"team_id,omitempty"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`                 // User who submitted the flag
+	Position    int                `bson:"position" json:"position"`               // Solve order of the challenge, 1 for first blood
+	Bonus       int                `bson:"bonus,omitempty" json:"bonus,omitempty"` // Blood bonus awarded with this solve
+	SolvedAt    time.Time          `bson:"solved_at" json:"solved_at"`
+}
+
+// BloodBadge returns the badge earned by the solver at the given 1-based position, if any
+func BloodBadge(position int) string {
+	switch position {
+	case 1:
+		return "first_blood"
+	case 2:
+		return "second_blood"
+	case 3:
+		return "third_blood"
+	}
+	return ""
+}
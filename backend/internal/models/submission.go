@@ -14,4 +14,11 @@ type Submission struct {
 	Flag        string             `bson:"flag" json:"flag"`
 	IsCorrect   bool               `bson:"is_correct" json:"is_correct"`
 	Timestamp   time.Time          `bson:"timestamp" json:"timestamp"`
+	RevokedAt   time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"` // Set when an admin revoked this solve; IsCorrect is then false
+	RevokedBy   primitive.ObjectID `bson:"revoked_by,omitempty" json:"revoked_by,omitempty"`
+}
+
+// IsRevoked reports whether the submission was a correct solve that an admin revoked
+func (s *Submission) IsRevoked() bool {
+	return !s.RevokedAt.IsZero()
 }
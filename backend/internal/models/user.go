@@ -18,6 +18,16 @@ type User struct {
 	ResetPasswordToken  string             `bson:"reset_password_token,omitempty" json:"-"`
 	ResetPasswordExpiry time.Time          `bson:"reset_password_expiry,omitempty" json:"-"`
 	OAuth               *OAuth             `bson:"oauth,omitempty" json:"oauth,omitempty"`
+	Banned              bool               `bson:"banned,omitempty" json:"banned,omitempty"`         // Banned users cannot log in or submit flags
+	BanReason           string             `bson:"ban_reason,omitempty" json:"ban_reason,omitempty"` // Shown to admins only
+	BannedAt            time.Time          `bson:"banned_at,omitempty" json:"banned_at,omitempty"`
+	TwoFactorEnabled    bool               `bson:"two_factor_enabled,omitempty" json:"two_factor_enabled"`
+	TOTPSecret          string             `bson:"totp_secret,omitempty" json:"-"`         // Base32 TOTP secret, set once enrollment is confirmed
+	TOTPPendingSecret   string             `bson:"totp_pending_secret,omitempty" json:"-"` // Secret awaiting a first valid code
+	TOTPLastStep        int64              `bson:"totp_last_step,omitempty" json:"-"`      // Last accepted time step, so codes cannot be replayed
+	RecoveryCodes       []string           `bson:"recovery_codes,omitempty" json:"-"`      // SHA-256 hashes of the unused recovery codes
+	TwoFactorFailures   int                `bson:"two_factor_failures,omitempty" json:"-"` // Wrong codes in a row
+	TwoFactorLockedAt   time.Time          `bson:"two_factor_locked_at,omitempty" json:"-"`
 	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt           time.Time          `bson:"updated_at" json:"updated_at"`
 }
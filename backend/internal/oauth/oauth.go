@@ -0,0 +1,52 @@
+package oauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+)
+
+// Identity is the account a provider vouches for after a successful login
+type Identity struct {
+	Provider      string
+	Subject       string // Stable user ID at the provider
+	Email         string
+	EmailVerified bool   // Whether the provider verified the email address
+	Username      string // Suggested username for new accounts
+}
+
+// Provider is an OAuth2 login provider using the authorization code flow with PKCE
+type Provider interface {
+	// Name identifies the provider in URLs and in User.OAuth
+	Name() string
+	// DisplayName is the label shown on the login button
+	DisplayName() string
+	// AuthCodeURL returns the URL the user is sent to; verifier is the PKCE code
+	// verifier and nonce binds the ID token to this login
+	AuthCodeURL(ctx context.Context, state, verifier, nonce string) (string, error)
+	// Exchange redeems an authorization code and returns the user's identity
+	Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error)
+}
+
+// NewProviders creates the providers that have a client ID configured
+func NewProviders(cfg *config.Config) []Provider {
+	base := strings.TrimRight(cfg.OAuthCallbackBaseURL, "/")
+	callbackURL := func(name string) string {
+		return base + "/auth/oauth/" + name + "/callback"
+	}
+
+	var providers []Provider
+	if cfg.GitHubClientID != "" {
+		providers = append(providers, NewGitHubProvider(cfg.GitHubClientID, cfg.GitHubClientSecret, callbackURL("github")))
+	}
+	if cfg.GoogleClientID != "" {
+		providers = append(providers, NewOIDCProvider("google", "Google", "https://accounts.google.com",
+			cfg.GoogleClientID, cfg.GoogleClientSecret, callbackURL("google")))
+	}
+	if cfg.OIDCIssuerURL != "" && cfg.OIDCClientID != "" {
+		providers = append(providers, NewOIDCProvider("oidc", cfg.OIDCDisplayName, cfg.OIDCIssuerURL,
+			cfg.OIDCClientID, cfg.OIDCClientSecret, callbackURL("oidc")))
+	}
+	return providers
+}
@@ -0,0 +1,128 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCProvider logs users in with any OpenID Connect issuer, such as Google or Keycloak
+type OIDCProvider struct {
+	name        string
+	displayName string
+	issuerURL   string
+	config      oauth2.Config
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func NewOIDCProvider(name, displayName, issuerURL, clientID, clientSecret, redirectURL string) *OIDCProvider {
+	return &OIDCProvider{
+		name:        name,
+		displayName: displayName,
+		issuerURL:   issuerURL,
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+		},
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+func (p *OIDCProvider) DisplayName() string {
+	return p.displayName
+}
+
+// discover fetches the issuer's discovery document on first use, so the server
+// starts even while the issuer is unreachable. Failures are retried on the next login.
+func (p *OIDCProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider == nil {
+		provider, err := oidc.NewProvider(ctx, p.issuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("oidc discovery for %s: %w", p.name, err)
+		}
+		p.provider = provider
+	}
+	return p.provider, nil
+}
+
+func (p *OIDCProvider) oauthConfig(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := p.config
+	config.Endpoint = provider.Endpoint()
+	return &config, provider, nil
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, verifier, nonce string) (string, error) {
+	config, _, err := p.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	return config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), oidc.Nonce(nonce)), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	config, provider, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("code exchange failed: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("provider returned no id_token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: config.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+
+	var claims struct {
+		Email             string      `json:"email"`
+		EmailVerified     interface{} `json:"email_verified"` // Some issuers send "true" as a string
+		PreferredUsername string      `json:"preferred_username"`
+		Nickname          string      `json:"nickname"`
+		Name              string      `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("invalid id_token claims: %w", err)
+	}
+
+	identity := &Identity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Username:      claims.PreferredUsername,
+	}
+	if identity.Username == "" {
+		identity.Username = claims.Nickname
+	}
+	if identity.Username == "" {
+		identity.Username = claims.Name
+	}
+	return identity, nil
+}
@@ -0,0 +1,49 @@
+package realtime
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+)
+
+// Event types streamed to clients
+const (
+	EventSolve        = "solve"
+	EventFirstBlood   = "first_blood"
+	EventScoreboard   = "scoreboard"
+	EventNotification = "notification"
+	EventRelease      = "challenge_release"
+)
+
+// Event is a message fanned out to every connected client
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	Time time.Time       `json:"time"`
+}
+
+// Broker fans events out to the subscribers of every API node
+type Broker interface {
+	// Publish sends an event to all subscribers
+	Publish(eventType string, data interface{}) error
+	// Subscribe returns a channel of events and a function that ends the subscription
+	Subscribe() (<-chan Event, func())
+}
+
+// NewBroker returns a Redis pub/sub broker when Redis is connected, so events reach
+// clients on every node, and an in-process broker otherwise
+func NewBroker() Broker {
+	if database.RDB != nil {
+		return NewRedisBroker(database.RDB)
+	}
+	return NewMemoryBroker()
+}
+
+func newEvent(eventType string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{Type: eventType, Data: raw, Time: time.Now()}, nil
+}
@@ -0,0 +1,58 @@
+package realtime
+
+import "sync"
+
+// subscriberBuffer is how many events a slow client may lag behind before events are dropped
+const subscriberBuffer = 64
+
+// MemoryBroker delivers events to subscribers in this process only
+type MemoryBroker struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+func (b *MemoryBroker) Publish(eventType string, data interface{}) error {
+	event, err := newEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+	b.deliver(event)
+	return nil
+}
+
+// deliver hands an event to every subscriber without blocking on slow ones
+func (b *MemoryBroker) deliver(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (b *MemoryBroker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
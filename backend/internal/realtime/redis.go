@@ -0,0 +1,65 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisChannel is the pub/sub channel shared by all API nodes
+const redisChannel = "ctf:events"
+
+// RedisBroker publishes events through Redis pub/sub and delivers the events
+// received from the channel to the subscribers of this node
+type RedisBroker struct {
+	client *redis.Client
+	local  *MemoryBroker
+}
+
+func NewRedisBroker(client *redis.Client) *RedisBroker {
+	b := &RedisBroker{
+		client: client,
+		local:  NewMemoryBroker(),
+	}
+	go b.listen()
+	return b
+}
+
+// listen forwards events from Redis to local subscribers; go-redis reconnects on its own
+func (b *RedisBroker) listen() {
+	pubsub := b.client.Subscribe(context.Background(), redisChannel)
+	defer pubsub.Close()
+
+	for msg := range pubsub.Channel() {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			log.Printf("Warning: dropping malformed realtime event: %v", err)
+			continue
+		}
+		b.local.deliver(event)
+	}
+}
+
+func (b *RedisBroker) Publish(eventType string, data interface{}) error {
+	event, err := newEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := b.client.Publish(context.Background(), redisChannel, payload).Err(); err != nil {
+		// Reach this node's clients at least
+		log.Printf("Warning: failed to publish realtime event to Redis: %v", err)
+		b.local.deliver(event)
+	}
+	return nil
+}
+
+func (b *RedisBroker) Subscribe() (<-chan Event, func()) {
+	return b.local.Subscribe()
+}
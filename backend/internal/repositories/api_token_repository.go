@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APITokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAPITokenRepository() *APITokenRepository {
+	return &APITokenRepository{
+		collection: database.DB.Collection("api_tokens"),
+	}
+}
+
+// EnsureIndexes creates the unique hash index, the per-user index and the TTL
+// index that removes expired tokens (tokens without an expiry are kept)
+func (r *APITokenRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+func (r *APITokenRepository) CreateToken(token *models.APIToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+// FindByHash returns an unexpired token
+func (r *APITokenRepository) FindByHash(hash string) (*models.APIToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"hash": hash,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": time.Now()}},
+		},
+	}
+	var token models.APIToken
+	if err := r.collection.FindOne(ctx, filter).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByUser returns a user's tokens, newest first
+func (r *APITokenRepository) FindByUser(userID primitive.ObjectID) ([]models.APIToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []models.APIToken
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// CountByUser counts a user's tokens
+func (r *APITokenRepository) CountByUser(userID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+}
+
+// TouchLastUsed records when and from where a token was last used
+func (r *APITokenRepository) TouchLastUsed(id primitive.ObjectID, at time.Time, ip string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"last_used_at": at, "last_used_ip": ip}}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// DeleteToken removes one of a user's tokens
+func (r *APITokenRepository) DeleteToken(id, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("token not found")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AwardRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAwardRepository() *AwardRepository {
+	return &AwardRepository{
+		collection: database.DB.Collection("awards"),
+	}
+}
+
+// CreateAward records a new award
+func (r *AwardRepository) CreateAward(award *models.Award) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	award.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, award)
+	if err != nil {
+		return err
+	}
+	award.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetAllAwards returns every award, newest first
+func (r *AwardRepository) GetAllAwards() ([]models.Award, error) {
+	return r.find(bson.M{})
+}
+
+// GetTeamAwards returns the awards applied to a team, newest first
+func (r *AwardRepository) GetTeamAwards(teamID primitive.ObjectID) ([]models.Award, error) {
+	return r.find(bson.M{"team_id": teamID})
+}
+
+// GetUserAwards returns the awards applied to a user, newest first
+func (r *AwardRepository) GetUserAwards(userID primitive.ObjectID) ([]models.Award, error) {
+	return r.find(bson.M{"user_id": userID})
+}
+
+func (r *AwardRepository) find(filter bson.M) ([]models.Award, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var awards []models.Award
+	if err = cursor.All(ctx, &awards); err != nil {
+		return nil, err
+	}
+	return awards, nil
+}
+
+// GetAwardByID returns an award by ID
+func (r *AwardRepository) GetAwardByID(id string) (*models.Award, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var award models.Award
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&award)
+	if err != nil {
+		return nil, err
+	}
+	return &award, nil
+}
+
+// UpdateAward changes the points and reason of an award
+func (r *AwardRepository) UpdateAward(award *models.Award) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	award.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"points":     award.Points,
+			"reason":     award.Reason,
+			"updated_at": award.UpdatedAt,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": award.ID}, update)
+	return err
+}
+
+// DeleteAward deletes an award by ID
+func (r *AwardRepository) DeleteAward(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ChallengeRepository struct {
@@ -21,6 +22,173 @@ func NewChallengeRepository() *ChallengeRepository {
 	}
 }
 
+// EnsureIndexes creates the unique index on challenge slugs (challenges without a
+// slug are not indexed), the index the release watcher queries, and the indexes
+// behind the filters and sort orders of FindChallenges
+func (r *ChallengeRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string", "$gt": ""}}),
+		},
+		{
+			Keys:    bson.D{{Key: "release_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "title", Value: 1}}},
+		{Keys: bson.D{{Key: "difficulty", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "solve_count", Value: -1}}},
+		{Keys: bson.D{{Key: "max_points", Value: -1}}},
+		{Keys: bson.D{{Key: "title", Value: 1}}},
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "description", Value: "text"},
+				{Key: "category", Value: "text"},
+				{Key: "tags", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("challenge_search").
+				SetWeights(bson.M{"title": 10, "tags": 5, "category": 3, "description": 1}),
+		},
+	})
+	return err
+}
+
+// Sort orders of FindChallenges
+const (
+	ChallengeSortDefault  = ""            // Text search relevance when searching, otherwise creation order
+	ChallengeSortPoints   = "max_points"  // Callers refine this to the current value, which is derived from the solve count
+	ChallengeSortSolves   = "solve_count" // Number of solves
+	ChallengeSortTitle    = "title"
+	ChallengeSortCategory = "category" // Category, then title
+)
+
+// ChallengeFilter selects and orders challenges for FindChallenges; zero fields match everything
+type ChallengeFilter struct {
+	Category   string
+	Difficulty string
+	Tags       []string             // Challenges must carry all of them
+	Search     string               // Text search over title, description, category and tags; see ChallengeService.FindChallenges
+	Solved     *bool                // Only challenges in SolvedIDs (true) or only the others (false)
+	SolvedIDs  []primitive.ObjectID // Challenges the player's team solved, used with Solved
+	ReleasedAt time.Time            // Only challenges players can see at this time
+	Sort       string               // One of the ChallengeSort orders
+	Descending bool
+}
+
+// FindChallenges returns the challenges matching the filter in the requested order
+func (r *ChallengeRepository) FindChallenges(filter ChallengeFilter) ([]models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := bson.M{}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.Difficulty != "" {
+		query["difficulty"] = filter.Difficulty
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	if filter.Search != "" {
+		query["$text"] = bson.M{"$search": filter.Search}
+	}
+	if filter.Solved != nil {
+		ids := filter.SolvedIDs
+		if ids == nil {
+			ids = []primitive.ObjectID{}
+		}
+		if *filter.Solved {
+			query["_id"] = bson.M{"$in": ids}
+		} else {
+			query["_id"] = bson.M{"$nin": ids}
+		}
+	}
+	if !filter.ReleasedAt.IsZero() {
+		// Mirrors models.Challenge.IsReleased
+		query["visibility"] = bson.M{"$nin": bson.A{models.VisibilityDraft, models.VisibilityHidden}}
+		query["$or"] = bson.A{
+			bson.M{"release_at": bson.M{"$exists": false}},
+			bson.M{"release_at": bson.M{"$lte": filter.ReleasedAt}},
+			bson.M{"visibility": models.VisibilityArchived},
+		}
+	}
+
+	order := 1
+	if filter.Descending {
+		order = -1
+	}
+	opts := options.Find()
+	switch filter.Sort {
+	case ChallengeSortPoints, ChallengeSortSolves, ChallengeSortTitle:
+		opts.SetSort(bson.D{{Key: filter.Sort, Value: order}, {Key: "_id", Value: 1}})
+	case ChallengeSortCategory:
+		opts.SetSort(bson.D{{Key: "category", Value: order}, {Key: "title", Value: 1}, {Key: "_id", Value: 1}})
+	default:
+		if filter.Search != "" {
+			score := bson.M{"$meta": "textScore"}
+			opts.SetProjection(bson.M{"score": score}).SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}})
+		} else {
+			opts.SetSort(bson.D{{Key: "_id", Value: order}})
+		}
+	}
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var challenges []models.Challenge
+	if err = cursor.All(ctx, &challenges); err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// GetChallengesReleasedBetween returns the visible challenges whose release time is in (from, to]
+func (r *ChallengeRepository) GetChallengesReleasedBetween(from, to time.Time) ([]models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"release_at": bson.M{"$gt": from, "$lte": to},
+		"visibility": bson.M{"$nin": bson.A{models.VisibilityDraft, models.VisibilityHidden, models.VisibilityArchived}},
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var challenges []models.Challenge
+	if err = cursor.All(ctx, &challenges); err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// GetChallengeBySlug returns the challenge with the given slug
+func (r *ChallengeRepository) GetChallengeBySlug(slug string) (*models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var challenge models.Challenge
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&challenge)
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
 func (r *ChallengeRepository) CreateChallenge(challenge *models.Challenge) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -28,8 +196,12 @@ func (r *ChallengeRepository) CreateChallenge(challenge *models.Challenge) error
 	// Initialize solve count to 0
 	challenge.SolveCount = 0
 
-	_, err := r.collection.InsertOne(ctx, challenge)
-	return err
+	result, err := r.collection.InsertOne(ctx, challenge)
+	if err != nil {
+		return err
+	}
+	challenge.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
 }
 
 func (r *ChallengeRepository) GetAllChallenges() ([]models.Challenge, error) {
@@ -77,17 +249,35 @@ func (r *ChallengeRepository) UpdateChallenge(id string, challenge *models.Chall
 
 	update := bson.M{
 		"$set": bson.M{
-			"title":       challenge.Title,
-			"description": challenge.Description,
-			"category":    challenge.Category,
-			"difficulty":  challenge.Difficulty,
-			"max_points":  challenge.MaxPoints,
-			"min_points":  challenge.MinPoints,
-			"decay":       challenge.Decay,
-			"flag_hash":   challenge.FlagHash,
-			"files":       challenge.Files,
+			"slug":           challenge.Slug,
+			"title":          challenge.Title,
+			"description":    challenge.Description,
+			"category":       challenge.Category,
+			"tags":           challenge.Tags,
+			"difficulty":     challenge.Difficulty,
+			"max_points":     challenge.MaxPoints,
+			"min_points":     challenge.MinPoints,
+			"decay":          challenge.Decay,
+			"scoring":        challenge.Scoring,
+			"flag_hash":      challenge.FlagHash,
+			"flags":          challenge.Flags,
+			"flag_mode":      challenge.FlagMode,
+			"flag_secret":    challenge.FlagSecret,
+			"flag_format":    challenge.FlagFormat,
+			"files":          challenge.Files,
+			"hints":          challenge.Hints,
+			"prerequisites":  challenge.Prerequisites,
+			"min_team_score": challenge.MinTeamScore,
+			"hide_locked":    challenge.HideLocked,
+			"blood_bonuses":  challenge.BloodBonuses,
+			"visibility":     challenge.Visibility,
 		},
 	}
+	if challenge.ReleaseAt.IsZero() {
+		update["$unset"] = bson.M{"release_at": ""}
+	} else {
+		update["$set"].(bson.M)["release_at"] = challenge.ReleaseAt
+	}
 
 	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
 	return err
@@ -106,6 +296,18 @@ func (r *ChallengeRepository) DeleteChallenge(id string) error {
 	return err
 }
 
+// RemovePrerequisite drops a challenge from every other challenge's prerequisites
+func (r *ChallengeRepository) RemovePrerequisite(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"prerequisites": id},
+		bson.M{"$pull": bson.M{"prerequisites": id}},
+	)
+	return err
+}
+
 // IncrementSolveCount increases the solve count for a challenge by 1
 func (r *ChallengeRepository) IncrementSolveCount(id string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -126,6 +328,44 @@ func (r *ChallengeRepository) IncrementSolveCount(id string) error {
 	return err
 }
 
+// AddAttachment appends an uploaded file to a challenge
+func (r *ChallengeRepository) AddAttachment(id string, file *models.ChallengeFile) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$push": bson.M{"attachments": file},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RemoveAttachment removes an uploaded file from a challenge
+func (r *ChallengeRepository) RemoveAttachment(id string, fileID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$pull": bson.M{"attachments": bson.M{"_id": fileID}},
+	})
+	return err
+}
+
 // GetFlagHash retrieves only the flag hash for verification (internal use)
 func (r *ChallengeRepository) GetFlagHash(id string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
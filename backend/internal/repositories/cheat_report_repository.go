@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CheatReportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCheatReportRepository() *CheatReportRepository {
+	return &CheatReportRepository{
+		collection: database.DB.Collection("cheat_reports"),
+	}
+}
+
+func (r *CheatReportRepository) CreateReport(report *models.CheatReport) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	report.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		return err
+	}
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetAllReports returns every cheating report, newest first
+func (r *CheatReportRepository) GetAllReports() ([]models.CheatReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reports []models.CheatReport
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEventRepository() *EventRepository {
+	return &EventRepository{
+		collection: database.DB.Collection("event_config"),
+	}
+}
+
+// GetConfig returns the event configuration, or an empty (always running) one if none is stored
+func (r *EventRepository) GetConfig() (*models.EventConfig, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var cfg models.EventConfig
+	err := r.collection.FindOne(ctx, bson.M{"_id": models.EventConfigID}).Decode(&cfg)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &models.EventConfig{ID: models.EventConfigID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SaveConfig creates or replaces the event configuration
+func (r *EventRepository) SaveConfig(cfg *models.EventConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cfg.ID = models.EventConfigID
+	cfg.UpdatedAt = time.Now()
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": models.EventConfigID}, cfg, opts)
+	return err
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type HintUnlockRepository struct {
+	collection *mongo.Collection
+}
+
+func NewHintUnlockRepository() *HintUnlockRepository {
+	return &HintUnlockRepository{
+		collection: database.DB.Collection("hint_unlocks"),
+	}
+}
+
+// ownerFilter scopes unlocks to a team, or to a single user when they have no team
+func ownerFilter(userID, teamID primitive.ObjectID) bson.M {
+	if !teamID.IsZero() {
+		return bson.M{"team_id": teamID}
+	}
+	return bson.M{"user_id": userID, "team_id": bson.M{"$exists": false}}
+}
+
+// EnsureIndexes creates the unique (hint, owner) index that keeps concurrent unlocks
+// from charging a team twice. Unlocks recorded before owner_id existed are not covered.
+func (r *HintUnlockRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "hint_id", Value: 1}, {Key: "owner_id", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"owner_id": bson.M{"$exists": true}}),
+	})
+	return err
+}
+
+// CreateUnlock records an unlock. It fails with a duplicate key error if the
+// team (or teamless user) already unlocked the hint.
+func (r *HintUnlockRepository) CreateUnlock(unlock *models.HintUnlock) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	unlock.OwnerID = unlock.UserID
+	if !unlock.TeamID.IsZero() {
+		unlock.OwnerID = unlock.TeamID
+	}
+	unlock.UnlockedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, unlock)
+	if err != nil {
+		return err
+	}
+	unlock.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindUnlock returns the unlock of a hint by the given team (or teamless user)
+func (r *HintUnlockRepository) FindUnlock(hintID, userID, teamID primitive.ObjectID) (*models.HintUnlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := ownerFilter(userID, teamID)
+	filter["hint_id"] = hintID
+
+	var unlock models.HintUnlock
+	err := r.collection.FindOne(ctx, filter).Decode(&unlock)
+	if err != nil {
+		return nil, err
+	}
+	return &unlock, nil
+}
+
+// GetChallengeUnlocksFor returns the hints of a challenge unlocked by a team (or teamless user)
+func (r *HintUnlockRepository) GetChallengeUnlocksFor(challengeID, userID, teamID primitive.ObjectID) ([]models.HintUnlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := ownerFilter(userID, teamID)
+	filter["challenge_id"] = challengeID
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var unlocks []models.HintUnlock
+	if err = cursor.All(ctx, &unlocks); err != nil {
+		return nil, err
+	}
+	return unlocks, nil
+}
+
+// GetTeamUnlocks returns all hints unlocked by a team
+func (r *HintUnlockRepository) GetTeamUnlocks(teamID primitive.ObjectID) ([]models.HintUnlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"team_id": teamID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var unlocks []models.HintUnlock
+	if err = cursor.All(ctx, &unlocks); err != nil {
+		return nil, err
+	}
+	return unlocks, nil
+}
+
+// GetUnlocksFor returns all hints unlocked by a team (or teamless user)
+func (r *HintUnlockRepository) GetUnlocksFor(userID, teamID primitive.ObjectID) ([]models.HintUnlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, ownerFilter(userID, teamID))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var unlocks []models.HintUnlock
+	if err = cursor.All(ctx, &unlocks); err != nil {
+		return nil, err
+	}
+	return unlocks, nil
+}
+
+// GetUnlocksByChallenge returns every unlock for a challenge, newest first (admin view)
+func (r *HintUnlockRepository) GetUnlocksByChallenge(challengeID primitive.ObjectID) ([]models.HintUnlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "unlocked_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"challenge_id": challengeID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var unlocks []models.HintUnlock
+	if err = cursor.All(ctx, &unlocks); err != nil {
+		return nil, err
+	}
+	return unlocks, nil
+}
+
+func (r *HintUnlockRepository) GetAllUnlocks() ([]models.HintUnlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var unlocks []models.HintUnlock
+	if err = cursor.All(ctx, &unlocks); err != nil {
+		return nil, err
+	}
+	return unlocks, nil
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		collection: database.DB.Collection("refresh_tokens"),
+	}
+}
+
+// EnsureIndexes creates the unique hash index, the per-session index and the TTL
+// index that removes expired tokens
+func (r *RefreshTokenRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "session_jti", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+func (r *RefreshTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+// FindByHash returns an unexpired token, used or not
+func (r *RefreshTokenRepository) FindByHash(hash string) (*models.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var token models.RefreshToken
+	filter := bson.M{"hash": hash, "expires_at": bson.M{"$gt": time.Now()}}
+	if err := r.collection.FindOne(ctx, filter).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed marks a token as rotated. It reports false if the token was already
+// used, so of two concurrent rotations only one succeeds.
+func (r *RefreshTokenRepository) MarkUsed(hash string, at time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"hash": hash, "used_at": bson.M{"$exists": false}}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"used_at": at}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// DeleteBySessions removes every token of the given sessions
+func (r *RefreshTokenRepository) DeleteBySessions(jtis []string) error {
+	if len(jtis) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"session_jti": bson.M{"$in": jtis}})
+	return err
+}
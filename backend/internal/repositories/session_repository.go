@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		collection: database.DB.Collection("sessions"),
+	}
+}
+
+// EnsureIndexes creates the unique JTI index, the per-user index and the TTL
+// index that removes expired sessions
+func (r *SessionRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "jti", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+func (r *SessionRepository) CreateSession(session *models.Session) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, session)
+	return err
+}
+
+// FindByJTI returns an unexpired session
+func (r *SessionRepository) FindByJTI(jti string) (*models.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var session models.Session
+	filter := bson.M{"jti": jti, "expires_at": bson.M{"$gt": time.Now()}}
+	if err := r.collection.FindOne(ctx, filter).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByUser returns a user's unexpired sessions, most recently used first
+func (r *SessionRepository) FindByUser(userID primitive.ObjectID) ([]models.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID, "expires_at": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Touch records that a session was just used
+func (r *SessionRepository) Touch(jti string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"jti": jti}, bson.M{"$set": bson.M{"last_seen_at": at}})
+	return err
+}
+
+// Extend moves a session's expiry, when its refresh token is rotated
+func (r *SessionRepository) Extend(jti string, expiresAt, lastSeenAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"expires_at": expiresAt, "last_seen_at": lastSeenAt}}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"jti": jti}, update)
+	return err
+}
+
+// DeleteByJTIs removes sessions, revoking their tokens
+func (r *SessionRepository) DeleteByJTIs(jtis []string) error {
+	if len(jtis) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"jti": bson.M{"$in": jtis}})
+	return err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SettingsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSettingsRepository() *SettingsRepository {
+	return &SettingsRepository{
+		collection: database.DB.Collection("settings"),
+	}
+}
+
+// GetSecuritySettings returns the security settings, or the defaults if none are stored
+func (r *SettingsRepository) GetSecuritySettings() (*models.SecuritySettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var settings models.SecuritySettings
+	err := r.collection.FindOne(ctx, bson.M{"_id": models.SecuritySettingsID}).Decode(&settings)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &models.SecuritySettings{ID: models.SecuritySettingsID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveSecuritySettings creates or replaces the security settings
+func (r *SettingsRepository) SaveSecuritySettings(settings *models.SecuritySettings) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	settings.ID = models.SecuritySettingsID
+	settings.UpdatedAt = time.Now()
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": models.SecuritySettingsID}, settings, opts)
+	return err
+}
@@ -0,0 +1,334 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// errAlreadySolved aborts the solve transaction when the owner already has a solve
+var errAlreadySolved = errors.New("already solved")
+
+type SolveRepository struct {
+	collection  *mongo.Collection
+	submissions *mongo.Collection
+	challenges  *mongo.Collection
+	teams       *mongo.Collection
+}
+
+func NewSolveRepository() *SolveRepository {
+	return &SolveRepository{
+		collection:  database.DB.Collection("solves"),
+		submissions: database.DB.Collection("submissions"),
+		challenges:  database.DB.Collection("challenges"),
+		teams:       database.DB.Collection("teams"),
+	}
+}
+
+// EnsureIndexes creates the unique (challenge, owner) index and, on first run,
+// backfills solves from the correct submissions recorded before it existed
+func (r *SolveRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	count, err := r.collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		if err := r.backfill(ctx); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "challenge_id", Value: 1}, {Key: "owner_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// backfill creates the earliest solve of every team (or teamless user) from past submissions,
+// numbering them in solve order
+func (r *SolveRepository) backfill(ctx context.Context) error {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+	cursor, err := r.submissions.Find(ctx, bson.M{"is_correct": true}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var submissions []models.Submission
+	if err = cursor.All(ctx, &submissions); err != nil {
+		return err
+	}
+
+	positions := make(map[primitive.ObjectID]int)
+	for _, sub := range submissions {
+		ownerID := sub.UserID
+		if !sub.TeamID.IsZero() {
+			ownerID = sub.TeamID
+		}
+		filter := bson.M{"challenge_id": sub.ChallengeID, "owner_id": ownerID}
+		update := bson.M{"$setOnInsert": models.Solve{
+			ChallengeID: sub.ChallengeID,
+			OwnerID:     ownerID,
+			TeamID:      sub.TeamID,
+			UserID:      sub.UserID,
+			Position:    positions[sub.ChallengeID] + 1,
+			SolvedAt:    sub.Timestamp,
+		}}
+		result, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+		if result.UpsertedCount > 0 {
+			positions[sub.ChallengeID]++
+		}
+	}
+	return nil
+}
+
+// RecordSolve stores a correct submission and, if it is the owner's first solve of the
+// challenge, increments the solve count and awards the team its points plus any blood
+// bonus. The solve, count and score change happen in one transaction where supported;
+// the unique solve index ensures concurrent submissions are only counted once either way.
+// It returns the challenge after the update and the new solve, which is nil if the
+// owner had already solved the challenge.
+func (r *SolveRepository) RecordSolve(submission *models.Submission, ownerID primitive.ObjectID) (*models.Challenge, *models.Solve, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	submission.Timestamp = time.Now()
+
+	var challenge models.Challenge
+	var solve *models.Solve
+	err := database.WithTransaction(ctx, func(ctx context.Context) error {
+		solve = &models.Solve{
+			ChallengeID: submission.ChallengeID,
+			OwnerID:     ownerID,
+			TeamID:      submission.TeamID,
+			UserID:      submission.UserID,
+			SolvedAt:    submission.Timestamp,
+		}
+		result, err := r.collection.InsertOne(ctx, solve)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return errAlreadySolved
+			}
+			return err
+		}
+		solve.ID = result.InsertedID.(primitive.ObjectID)
+
+		if _, err := r.submissions.InsertOne(ctx, submission); err != nil {
+			return err
+		}
+
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		err = r.challenges.FindOneAndUpdate(ctx,
+			bson.M{"_id": submission.ChallengeID},
+			bson.M{"$inc": bson.M{"solve_count": 1}},
+			opts,
+		).Decode(&challenge)
+		if err != nil {
+			return err
+		}
+
+		// The incremented solve count is this solve's position
+		solve.Position = challenge.SolveCount
+		solve.Bonus = challenge.BloodBonus(solve.Position)
+		_, err = r.collection.UpdateOne(ctx,
+			bson.M{"_id": solve.ID},
+			bson.M{"$set": bson.M{"position": solve.Position, "bonus": solve.Bonus}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if submission.TeamID.IsZero() {
+			return nil
+		}
+		_, err = r.teams.UpdateOne(ctx,
+			bson.M{"_id": submission.TeamID},
+			bson.M{
+				"$inc": bson.M{"score": challenge.CurrentPoints() + solve.Bonus},
+				"$set": bson.M{"updated_at": time.Now()},
+			},
+		)
+		return err
+	})
+
+	if errors.Is(err, errAlreadySolved) {
+		// Someone on the team got there first; keep the submission for the record,
+		// unless it repeats one of the user's own correct submissions, which the
+		// user scoreboard would otherwise credit twice
+		repeated, err := r.submissions.CountDocuments(ctx, bson.M{
+			"challenge_id": submission.ChallengeID,
+			"user_id":      submission.UserID,
+			"is_correct":   true,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if repeated == 0 {
+			if _, err := r.submissions.InsertOne(ctx, submission); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := r.challenges.FindOne(ctx, bson.M{"_id": submission.ChallengeID}).Decode(&challenge); err != nil {
+			return nil, nil, err
+		}
+		return &challenge, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &challenge, solve, nil
+}
+
+func (r *SolveRepository) find(filter bson.M) ([]models.Solve, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var solves []models.Solve
+	if err = cursor.All(ctx, &solves); err != nil {
+		return nil, err
+	}
+	return solves, nil
+}
+
+// GetSolveByID returns a solve by ID
+func (r *SolveRepository) GetSolveByID(id string) (*models.Solve, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var solve models.Solve
+	if err := r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&solve); err != nil {
+		return nil, err
+	}
+	return &solve, nil
+}
+
+// GetChallengeSolves returns the solves of a challenge in solve order
+func (r *SolveRepository) GetChallengeSolves(challengeID primitive.ObjectID) ([]models.Solve, error) {
+	solves, err := r.find(bson.M{"challenge_id": challengeID})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(solves, func(i, j int) bool {
+		return solves[i].Position < solves[j].Position
+	})
+	return solves, nil
+}
+
+// GetAllSolves returns every recorded solve
+func (r *SolveRepository) GetAllSolves() ([]models.Solve, error) {
+	return r.find(bson.M{})
+}
+
+// GetOwnerSolves returns the solves of a team (or teamless user)
+func (r *SolveRepository) GetOwnerSolves(ownerID primitive.ObjectID) ([]models.Solve, error) {
+	return r.find(bson.M{"owner_id": ownerID})
+}
+
+// GetUserSolves returns the solves submitted by a user, for their team or themselves
+func (r *SolveRepository) GetUserSolves(userID primitive.ObjectID) ([]models.Solve, error) {
+	return r.find(bson.M{"user_id": userID})
+}
+
+// RescoreChanges is the set of writes that brings solves, solve counts and team
+// scores back in line with the correct submissions
+type RescoreChanges struct {
+	RevokeSubmissions []primitive.ObjectID // Correct submissions to mark as revoked
+	RevokedBy         primitive.ObjectID
+	DeleteSolves      []primitive.ObjectID
+	UpdateSolves      []models.Solve // Solves whose position or bonus changed
+	InsertSolves      []models.Solve
+	SolveCounts       map[primitive.ObjectID]int // Challenge ID -> solve count
+	TeamScores        map[primitive.ObjectID]int // Team ID -> score
+}
+
+// ApplyRescore writes a rescore in one transaction where supported. Solves are
+// deleted before others are inserted so the unique index never gets in the way.
+func (r *SolveRepository) ApplyRescore(changes *RescoreChanges) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	return database.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		if len(changes.RevokeSubmissions) > 0 {
+			_, err := r.submissions.UpdateMany(ctx,
+				bson.M{"_id": bson.M{"$in": changes.RevokeSubmissions}},
+				bson.M{"$set": bson.M{"is_correct": false, "revoked_at": now, "revoked_by": changes.RevokedBy}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(changes.DeleteSolves) > 0 {
+			_, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": changes.DeleteSolves}})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, solve := range changes.UpdateSolves {
+			_, err := r.collection.UpdateOne(ctx,
+				bson.M{"_id": solve.ID},
+				bson.M{"$set": bson.M{"position": solve.Position, "bonus": solve.Bonus}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, solve := range changes.InsertSolves {
+			if _, err := r.collection.InsertOne(ctx, solve); err != nil {
+				return err
+			}
+		}
+
+		for id, count := range changes.SolveCounts {
+			_, err := r.challenges.UpdateOne(ctx,
+				bson.M{"_id": id},
+				bson.M{"$set": bson.M{"solve_count": count}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		for id, score := range changes.TeamScores {
+			_, err := r.teams.UpdateOne(ctx,
+				bson.M{"_id": id},
+				bson.M{"$set": bson.M{"score": score, "updated_at": now}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type SubmissionRepository struct {
@@ -21,6 +22,21 @@ func NewSubmissionRepository() *SubmissionRepository {
 	}
 }
 
+// EnsureIndexes creates the indexes backing the admin submissions browser
+func (r *SubmissionRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "team_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "challenge_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "is_correct", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+	return err
+}
+
 func (r *SubmissionRepository) CreateSubmission(submission *models.Submission) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -135,3 +151,80 @@ func (r *SubmissionRepository) GetUserCorrectSubmissionCount(userID primitive.Ob
 
 	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "is_correct": true})
 }
+
+// SubmissionCursor marks the position after the last submission of a page
+type SubmissionCursor struct {
+	Timestamp time.Time
+	ID        primitive.ObjectID
+}
+
+// SubmissionFilter selects submissions for the admin browser; zero fields are not filtered on
+type SubmissionFilter struct {
+	UserID      primitive.ObjectID
+	TeamID      primitive.ObjectID
+	ChallengeID primitive.ObjectID
+	IsCorrect   *bool
+	From        time.Time // Inclusive
+	To          time.Time // Exclusive
+	Ascending   bool      // Oldest first instead of newest first
+	After       *SubmissionCursor
+	Limit       int
+}
+
+// FindSubmissions returns up to filter.Limit submissions matching the filter,
+// ordered by time and then ID so that pages never skip or repeat a submission
+func (r *SubmissionRepository) FindSubmissions(filter SubmissionFilter) ([]models.Submission, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := bson.M{}
+	if !filter.UserID.IsZero() {
+		query["user_id"] = filter.UserID
+	}
+	if !filter.TeamID.IsZero() {
+		query["team_id"] = filter.TeamID
+	}
+	if !filter.ChallengeID.IsZero() {
+		query["challenge_id"] = filter.ChallengeID
+	}
+	if filter.IsCorrect != nil {
+		query["is_correct"] = *filter.IsCorrect
+	}
+
+	timeRange := bson.M{}
+	if !filter.From.IsZero() {
+		timeRange["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timeRange["$lt"] = filter.To
+	}
+	if len(timeRange) > 0 {
+		query["timestamp"] = timeRange
+	}
+
+	order, cmp := -1, "$lt"
+	if filter.Ascending {
+		order, cmp = 1, "$gt"
+	}
+	if filter.After != nil {
+		query["$or"] = bson.A{
+			bson.M{"timestamp": bson.M{cmp: filter.After.Timestamp}},
+			bson.M{"timestamp": filter.After.Timestamp, "_id": bson.M{cmp: filter.After.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(int64(filter.Limit))
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var submissions []models.Submission
+	if err = cursor.All(ctx, &submissions); err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
@@ -27,8 +27,12 @@ func (r *UserRepository) CreateUser(user *models.User) error {
 
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
-	_, err := r.collection.InsertOne(ctx, user)
-	return err
+	result, err := r.collection.InsertOne(ctx, user)
+	if err != nil {
+		return err
+	}
+	user.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
 }
 
 func (r *UserRepository) UpdateUser(user *models.User) error {
@@ -82,6 +86,19 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByOAuth finds the user linked to an account at an OAuth provider
+func (r *UserRepository) FindByOAuth(provider, providerID string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := r.collection.FindOne(ctx, bson.M{"oauth.provider": provider, "oauth.provider_id": providerID}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *UserRepository) FindByVerificationToken(token string) (*models.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -122,3 +139,91 @@ func (r *UserRepository) GetAllUsers() ([]models.User, error) {
 	}
 	return users, nil
 }
+
+// RecordTwoFactorAttempt counts a two-factor code attempt before it is checked, so
+// concurrent guesses cannot get past the lockout. It reports false, without
+// counting, while the user is locked out: maxFailures attempts in a row lock the
+// user until the lockout started before lockedBefore.
+func (r *UserRepository) RecordTwoFactorAttempt(userID primitive.ObjectID, maxFailures int, lockedBefore, now time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": userID, "$or": bson.A{
+		bson.M{"two_factor_failures": bson.M{"$not": bson.M{"$gte": maxFailures}}},
+		bson.M{"two_factor_locked_at": bson.M{"$lte": lockedBefore}},
+	}}
+	failures := bson.M{"$ifNull": bson.A{"$two_factor_failures", 0}}
+	update := mongo.Pipeline{
+		// An expired lockout starts a new count
+		{{Key: "$set", Value: bson.M{"two_factor_failures": bson.M{"$cond": bson.A{
+			bson.M{"$gte": bson.A{failures, maxFailures}}, 1, bson.M{"$add": bson.A{failures, 1}},
+		}}}}},
+		{{Key: "$set", Value: bson.M{"two_factor_locked_at": bson.M{"$cond": bson.A{
+			bson.M{"$gte": bson.A{"$two_factor_failures", maxFailures}}, now, "$two_factor_locked_at",
+		}}}}},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// UseTOTPStep records an accepted TOTP time step and clears the failed attempts.
+// It reports false if the step or a later one was already used, so of two
+// concurrent logins with the same code only one succeeds.
+func (r *UserRepository) UseTOTPStep(userID primitive.ObjectID, step int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": userID, "totp_last_step": bson.M{"$not": bson.M{"$gte": step}}}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set":   bson.M{"totp_last_step": step, "updated_at": time.Now()},
+		"$unset": bson.M{"two_factor_failures": "", "two_factor_locked_at": ""},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UseRecoveryCode removes a recovery code hash and clears the failed attempts. It
+// reports false if the code was already used.
+func (r *UserRepository) UseRecoveryCode(userID primitive.ObjectID, hash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": userID, "recovery_codes": hash}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$pull":  bson.M{"recovery_codes": hash},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"two_factor_failures": "", "two_factor_locked_at": ""},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// SetRecoveryCodes replaces the recovery code hashes
+func (r *UserRepository) SetRecoveryCodes(userID primitive.ObjectID, hashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$set": bson.M{"recovery_codes": hashes, "updated_at": time.Now()},
+	})
+	return err
+}
+
+// DisableTwoFactor removes the user's TOTP secret and recovery codes
+func (r *UserRepository) DisableTwoFactor(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"two_factor_enabled": "", "totp_secret": "", "totp_last_step": "", "recovery_codes": ""},
+	})
+	return err
+}
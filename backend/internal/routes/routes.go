@@ -1,14 +1,19 @@
 package routes
 
 import (
+	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-ctf-platform/backend/internal/config"
 	"github.com/go-ctf-platform/backend/internal/handlers"
 	"github.com/go-ctf-platform/backend/internal/middleware"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/oauth"
+	"github.com/go-ctf-platform/backend/internal/realtime"
 	"github.com/go-ctf-platform/backend/internal/repositories"
 	"github.com/go-ctf-platform/backend/internal/services"
+	"github.com/go-ctf-platform/backend/internal/storage"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -40,40 +45,126 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	teamRepo := repositories.NewTeamRepository()
 	teamInvitationRepo := repositories.NewTeamInvitationRepository()
 	notificationRepo := repositories.NewNotificationRepository()
+	hintUnlockRepo := repositories.NewHintUnlockRepository()
+	cheatReportRepo := repositories.NewCheatReportRepository()
+	eventRepo := repositories.NewEventRepository()
+	solveRepo := repositories.NewSolveRepository()
+	awardRepo := repositories.NewAwardRepository()
+	settingsRepo := repositories.NewSettingsRepository()
+	sessionRepo := repositories.NewSessionRepository()
+	refreshTokenRepo := repositories.NewRefreshTokenRepository()
+	apiTokenRepo := repositories.NewAPITokenRepository()
+
+	// The unique index on solves is what keeps concurrent submissions from double counting
+	if err := solveRepo.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create solve indexes:", err)
+	}
+	if err := submissionRepo.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create submission indexes:", err)
+	}
+	if err := challengeRepo.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create challenge indexes:", err)
+	}
+	if err := sessionRepo.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create session indexes:", err)
+	}
+	if err := refreshTokenRepo.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create refresh token indexes:", err)
+	}
+	if err := apiTokenRepo.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create API token indexes:", err)
+	}
+
+	if err := hintUnlockRepo.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create hint unlock indexes:", err)
+	}
+
+	// Storage backend for challenge attachments
+	fileStorage, err := storage.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize file storage:", err)
+	}
+
+	// Realtime events, fanned out across nodes through Redis when available
+	broker := realtime.NewBroker()
 
 	// Services
 	emailService := services.NewEmailService(cfg)
-	authService := services.NewAuthService(userRepo, emailService, cfg)
-	challengeService := services.NewChallengeService(challengeRepo, submissionRepo, teamRepo)
-	scoreboardService := services.NewScoreboardService(userRepo, submissionRepo, challengeRepo, teamRepo)
-	teamService := services.NewTeamService(teamRepo, teamInvitationRepo, userRepo, emailService, submissionRepo, challengeRepo)
-	notificationService := services.NewNotificationService(notificationRepo)
+	sessionService := services.NewSessionService(sessionRepo, refreshTokenRepo)
+	apiTokenService := services.NewAPITokenService(apiTokenRepo, userRepo)
+	twoFactorService := services.NewTwoFactorService(userRepo, settingsRepo, cfg)
+	authService := services.NewAuthService(userRepo, emailService, twoFactorService, sessionService, cfg)
+	oauthService := services.NewOAuthService(oauth.NewProviders(cfg), userRepo, authService, cfg)
+	eventService := services.NewEventService(eventRepo)
+	fileService := services.NewFileService(challengeRepo, fileStorage, cfg.MaxUploadSizeMB)
+	challengeService := services.NewChallengeService(challengeRepo, submissionRepo, teamRepo, fileService, userRepo, cheatReportRepo, hintUnlockRepo, solveRepo, awardRepo, eventService, broker)
+	scoreboardService := services.NewScoreboardService(userRepo, submissionRepo, challengeRepo, teamRepo, hintUnlockRepo, solveRepo, awardRepo, eventService)
+	teamService := services.NewTeamService(teamRepo, teamInvitationRepo, userRepo, emailService, submissionRepo, challengeRepo, hintUnlockRepo, solveRepo, awardRepo)
+	notificationService := services.NewNotificationService(notificationRepo, broker)
+	hintService := services.NewHintService(challengeRepo, hintUnlockRepo, teamRepo, userRepo)
+	awardService := services.NewAwardService(awardRepo, teamRepo, userRepo)
+	submissionService := services.NewSubmissionService(submissionRepo, challengeRepo, teamRepo, userRepo)
+	rescoreService := services.NewRescoreService(submissionRepo, challengeRepo, teamRepo, userRepo, hintUnlockRepo, solveRepo, awardRepo)
+	challengeImportService := services.NewChallengeImportService(challengeService, challengeRepo, fileService)
+
+	// Invalidate caches and notify clients when scheduled challenges go live
+	go challengeService.WatchReleases(services.ReleaseCheckInterval)
+
+	// Forget rate limit attempts once they fall out of the one minute windows used below
+	go middleware.CleanupExpiredAttempts(time.Minute, 5*time.Minute)
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	challengeHandler := handlers.NewChallengeHandler(challengeService)
+	oauthHandler := handlers.NewOAuthHandler(oauthService, cfg)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+	challengeHandler := handlers.NewChallengeHandler(challengeService, eventService)
 	scoreboardHandler := handlers.NewScoreboardHandler(scoreboardService)
 	teamHandler := handlers.NewTeamHandler(teamService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
-	profileHandler := handlers.NewProfileHandler(userRepo, submissionRepo, challengeRepo, teamRepo)
+	profileHandler := handlers.NewProfileHandler(userRepo, submissionRepo, challengeRepo, teamRepo, solveRepo, awardRepo)
+	hintHandler := handlers.NewHintHandler(hintService, challengeService, eventService)
+	fileHandler := handlers.NewFileHandler(fileService, challengeService, eventService)
+	eventHandler := handlers.NewEventHandler(eventService)
+	streamHandler := handlers.NewStreamHandler(broker)
+	awardHandler := handlers.NewAwardHandler(awardService)
+	submissionHandler := handlers.NewSubmissionHandler(submissionService)
+	rescoreHandler := handlers.NewRescoreHandler(rescoreService)
+	challengeImportHandler := handlers.NewChallengeImportHandler(challengeImportService, fileService)
 
 	// Public Routes - Authentication
 	r.POST("/auth/register", authHandler.Register)
 	r.POST("/auth/login", authHandler.Login)
+	r.POST("/auth/login/2fa", middleware.IPRateLimitMiddleware(10, time.Minute), authHandler.LoginTwoFactor)
 	r.POST("/auth/logout", authHandler.Logout)
+	r.POST("/auth/refresh", authHandler.Refresh)
 	r.GET("/auth/verify-email", authHandler.VerifyEmail)
 	r.POST("/auth/verify-email", authHandler.VerifyEmail)
 	r.POST("/auth/resend-verification", authHandler.ResendVerification)
 	r.POST("/auth/forgot-password", authHandler.ForgotPassword)
 	r.POST("/auth/reset-password", authHandler.ResetPassword)
 
+	// Public Routes - OAuth2/OIDC login
+	r.GET("/auth/oauth/providers", oauthHandler.GetProviders)
+	r.GET("/auth/oauth/:provider/login", oauthHandler.Login)
+	r.GET("/auth/oauth/:provider/callback", oauthHandler.Callback)
+
 	// Public Routes - Scoreboard (team scoreboard)
 	r.GET("/scoreboard", scoreboardHandler.GetScoreboard)
 	r.GET("/scoreboard/teams", scoreboardHandler.GetTeamScoreboard)
+	r.GET("/scoreboard/history", scoreboardHandler.GetScoreHistory)
+	r.GET("/scoreboard/ctftime", scoreboardHandler.GetCTFtimeScoreboard)
+
+	// Public Routes - Event schedule
+	r.GET("/event", eventHandler.GetEvent)
 
 	// Public Routes - Notifications (active notifications only)
 	r.GET("/notifications", notificationHandler.GetActiveNotifications)
 
+	// Public Routes - Live solve, scoreboard and notification feed (Server-Sent Events)
+	r.GET("/stream", streamHandler.Stream)
+
 	// Public Routes - User Profiles
 	r.GET("/users/:username/profile", profileHandler.GetUserProfile)
 
@@ -96,6 +187,13 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		}
 
 		claims, _ := token.Claims.(jwt.MapClaims)
+		userID, _ := claims["user_id"].(string)
+		jti, _ := claims["jti"].(string)
+		if sessionService.ValidateSession(jti, userID) != nil {
+			c.JSON(401, gin.H{"authenticated": false})
+			return
+		}
+
 		c.JSON(200, gin.H{
 			"authenticated": true,
 			"user": gin.H{
@@ -104,27 +202,59 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 				"email":    claims["email"],
 				"role":     claims["role"],
 			},
+			"two_factor": claims["two_factor"] == true,
 		})
 	})
 
-	// Protected Routes
+	// Protected Routes that also accept personal API tokens (Authorization: Token), by scope
+	tokenAPI := r.Group("/")
+	tokenAPI.Use(middleware.AuthMiddleware(cfg, sessionService, apiTokenService))
+	{
+		readChallenges := middleware.RequireScope(models.ScopeChallengesRead)
+		tokenAPI.GET("/challenges", readChallenges, challengeHandler.GetAllChallenges)
+		tokenAPI.GET("/challenges/:id", readChallenges, challengeHandler.GetChallengeByID)
+		// Flag submission with rate limiting (5 attempts per minute per challenge, shared by sessions and tokens)
+		tokenAPI.POST("/challenges/:id/submit", middleware.RequireScope(models.ScopeFlagsSubmit), middleware.RateLimitMiddleware(5, time.Minute), challengeHandler.SubmitFlag)
+		tokenAPI.GET("/challenges/:id/files/:fileId", readChallenges, fileHandler.DownloadFile)
+		tokenAPI.GET("/challenges/:id/hints", readChallenges, hintHandler.GetHints)
+
+		readTeam := middleware.RequireScope(models.ScopeTeamRead)
+		tokenAPI.GET("/teams/my-team", readTeam, teamHandler.GetMyTeam)
+		tokenAPI.GET("/teams/:id", readTeam, teamHandler.GetTeamDetails)
+		tokenAPI.GET("/teams/:id/awards", readTeam, awardHandler.GetTeamAwards)
+	}
+
+	// Protected Routes (sessions only)
 	protected := r.Group("/")
-	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.AuthMiddleware(cfg, sessionService, nil))
 	{
 		// User Routes
 		protected.POST("/auth/change-password", authHandler.ChangePassword)
-		protected.GET("/challenges", challengeHandler.GetAllChallenges)
-		protected.GET("/challenges/:id", challengeHandler.GetChallengeByID)
-		// Flag submission with rate limiting (5 attempts per minute per challenge)
-		protected.POST("/challenges/:id/submit", middleware.RateLimitMiddleware(5, time.Minute), challengeHandler.SubmitFlag)
+
+		// Two-factor authentication
+		protected.GET("/auth/2fa", twoFactorHandler.GetStatus)
+		protected.POST("/auth/2fa/enroll", twoFactorHandler.BeginEnrollment)
+		protected.POST("/auth/2fa/confirm", twoFactorHandler.ConfirmEnrollment)
+		protected.POST("/auth/2fa/disable", twoFactorHandler.Disable)
+		protected.POST("/auth/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+
+		// Active sessions
+		protected.GET("/auth/sessions", sessionHandler.GetSessions)
+		protected.DELETE("/auth/sessions", sessionHandler.RevokeOtherSessions)
+		protected.DELETE("/auth/sessions/:id", sessionHandler.RevokeSession)
+
+		// Personal API tokens
+		protected.GET("/auth/tokens", apiTokenHandler.GetTokens)
+		protected.POST("/auth/tokens", apiTokenHandler.CreateToken)
+		protected.DELETE("/auth/tokens/:id", apiTokenHandler.RevokeToken)
+
+		protected.POST("/challenges/:id/hints/:hintId/unlock", hintHandler.UnlockHint)
 
 		// Team Routes
 		teams := protected.Group("/teams")
 		{
-			// Team creation and viewing
+			// Team creation and management (viewing is registered with the token routes)
 			teams.POST("", teamHandler.CreateTeam)
-			teams.GET("/my-team", teamHandler.GetMyTeam)
-			teams.GET("/:id", teamHandler.GetTeamDetails)
 			teams.PUT("/:id", teamHandler.UpdateTeam)
 			teams.DELETE("/:id", teamHandler.DeleteTeam)
 
@@ -152,13 +282,38 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 
 		// Admin Routes
 		admin := protected.Group("/admin")
-		admin.Use(middleware.AdminMiddleware())
+		admin.Use(middleware.AdminMiddleware(twoFactorService))
 		{
 			// Challenge management
 			admin.GET("/challenges", challengeHandler.GetAllChallengesWithFlags)
 			admin.POST("/challenges", challengeHandler.CreateChallenge)
+			admin.POST("/challenges/scoring-preview", challengeHandler.PreviewScoring)
+			admin.POST("/challenges/import", challengeImportHandler.ImportChallenges)
+			admin.GET("/challenges/export", challengeImportHandler.ExportChallenges)
 			admin.PUT("/challenges/:id", challengeHandler.UpdateChallenge)
 			admin.DELETE("/challenges/:id", challengeHandler.DeleteChallenge)
+			admin.GET("/challenges/:id/hints/unlocks", hintHandler.GetHintUnlocks)
+			admin.GET("/challenges/:id/flags", challengeHandler.GetDynamicFlags)
+			admin.GET("/cheat-reports", challengeHandler.GetCheatReports)
+			admin.GET("/submissions", submissionHandler.GetSubmissions)
+
+			// Solve revocation and rescoring (all accept ?dry_run=true)
+			admin.GET("/challenges/:id/solves", rescoreHandler.GetChallengeSolves)
+			admin.POST("/challenges/:id/reset", rescoreHandler.ResetChallenge)
+			admin.POST("/solves/:id/revoke", rescoreHandler.RevokeSolve)
+			admin.POST("/rescore", rescoreHandler.Recompute)
+			admin.POST("/challenges/:id/files", fileHandler.UploadFile)
+			admin.DELETE("/challenges/:id/files/:fileId", fileHandler.DeleteFile)
+
+			// Event schedule and live (unfrozen) scoreboards
+			admin.GET("/event", eventHandler.GetEvent)
+			admin.PUT("/event", eventHandler.UpdateEvent)
+			admin.GET("/scoreboard", scoreboardHandler.GetLiveScoreboard)
+			admin.GET("/scoreboard/teams", scoreboardHandler.GetLiveTeamScoreboard)
+
+			// Account security policies
+			admin.GET("/settings/security", twoFactorHandler.GetSecuritySettings)
+			admin.PUT("/settings/security", twoFactorHandler.UpdateSecuritySettings)
 
 			// Notification management
 			admin.GET("/notifications", notificationHandler.GetAllNotifications)
@@ -166,6 +321,13 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 			admin.PUT("/notifications/:id", notificationHandler.UpdateNotification)
 			admin.DELETE("/notifications/:id", notificationHandler.DeleteNotification)
 			admin.POST("/notifications/:id/toggle", notificationHandler.ToggleNotificationActive)
+
+			// Manual awards and penalties
+			admin.GET("/awards", awardHandler.GetAllAwards)
+			admin.POST("/awards", awardHandler.CreateAward)
+			admin.GET("/awards/:id", awardHandler.GetAward)
+			admin.PUT("/awards/:id", awardHandler.UpdateAward)
+			admin.DELETE("/awards/:id", awardHandler.DeleteAward)
 		}
 	}
 
@@ -11,48 +11,66 @@ import (
 )
 
 type AdminService struct {
-	userRepo *repositories.UserRepository
+	userRepo       *repositories.UserRepository
+	sessionService *SessionService
 }
 
-func NewAdminService(userRepo *repositories.UserRepository) *AdminService {
+func NewAdminService(userRepo *repositories.UserRepository, sessionService *SessionService) *AdminService {
 	return &AdminService{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
 	}
 }
 
 // CreateAdminUser creates a new admin user with email already verified
 func (s *AdminService) CreateAdminUser(username, email, password string) error {
+	_, err := s.CreateUser(username, email, password, "admin")
+	return err
+}
+
+// CreateUser creates a user with the given role ("admin" or "user") and email already verified
+func (s *AdminService) CreateUser(username, email, password, role string) (*models.User, error) {
+	if role != "admin" && role != "user" {
+		return nil, errors.New("role must be admin or user")
+	}
+	if username == "" || email == "" || password == "" {
+		return nil, errors.New("username, email and password are required")
+	}
+
 	// Check if username already exists
 	existingUser, _ := s.userRepo.FindByUsername(username)
 	if existingUser != nil {
-		return errors.New("username already exists")
+		return nil, errors.New("username already exists")
 	}
 
 	// Check if email already exists
 	existingEmail, _ := s.userRepo.FindByEmail(email)
 	if existingEmail != nil {
-		return errors.New("email already registered")
+		return nil, errors.New("email already registered")
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create admin user
+	// Create user
 	user := &models.User{
 		ID:            primitive.NewObjectID(),
 		Username:      username,
 		Email:         email,
 		PasswordHash:  string(hashedPassword),
-		Role:          "admin",
-		EmailVerified: true, // Auto-verify admin users
+		Role:          role,
+		EmailVerified: true, // Auto-verify users created by admins
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	return s.userRepo.CreateUser(user)
+	if err := s.userRepo.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 // PromoteToAdmin promotes an existing user to admin role
@@ -78,6 +96,11 @@ func (s *AdminService) PromoteToAdmin(usernameOrEmail string) (*models.User, err
 		return nil, err
 	}
 
+	// The role is part of the session token; sign the user out so the change applies now
+	if _, err := s.sessionService.RevokeAllSessions(user.ID, ""); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -104,6 +127,11 @@ func (s *AdminService) DemoteToUser(usernameOrEmail string) (*models.User, error
 		return nil, err
 	}
 
+	// The role is part of the session token; sign the user out so the change applies now
+	if _, err := s.sessionService.RevokeAllSessions(user.ID, ""); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -125,3 +153,52 @@ func (s *AdminService) FindUser(usernameOrEmail string) (*models.User, error) {
 	}
 	return user, nil
 }
+
+// BanUser bans a user, who can then no longer log in or submit flags
+func (s *AdminService) BanUser(usernameOrEmail, reason string) (*models.User, error) {
+	user, err := s.FindUser(usernameOrEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Banned {
+		return nil, errors.New("user is already banned")
+	}
+
+	user.Banned = true
+	user.BanReason = reason
+	user.BannedAt = time.Now()
+
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	// Sign the user out everywhere
+	if _, err := s.sessionService.RevokeAllSessions(user.ID, ""); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UnbanUser lifts a user's ban
+func (s *AdminService) UnbanUser(usernameOrEmail string) (*models.User, error) {
+	user, err := s.FindUser(usernameOrEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.Banned {
+		return nil, errors.New("user is not banned")
+	}
+
+	user.Banned = false
+	user.BanReason = ""
+	user.BannedAt = time.Time{}
+
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// APITokenPrefix starts every personal API token, so leaked tokens are easy to spot
+	APITokenPrefix = "ctf_"
+	// MaxAPITokens is how many tokens a user may have at once
+	MaxAPITokens = 20
+	// MaxAPITokenDays is the longest expiry a token can be created with
+	MaxAPITokenDays = 365
+	// apiTokenTouchInterval is how often a token's last-used time is written
+	apiTokenTouchInterval = time.Minute
+)
+
+// ErrInvalidAPIToken is returned for unknown, expired or revoked tokens
+var ErrInvalidAPIToken = errors.New("invalid or expired API token")
+
+type APITokenService struct {
+	apiTokenRepo *repositories.APITokenRepository
+	userRepo     *repositories.UserRepository
+}
+
+func NewAPITokenService(apiTokenRepo *repositories.APITokenRepository, userRepo *repositories.UserRepository) *APITokenService {
+	return &APITokenService{
+		apiTokenRepo: apiTokenRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func hashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// CreateToken creates a personal API token and returns it with its secret value,
+// which is not stored and cannot be shown again. expiresInDays 0 means no expiry.
+func (s *APITokenService) CreateToken(userID, name string, scopes []string, expiresInDays int) (*models.APIToken, string, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, "", errors.New("invalid user ID")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return nil, "", errors.New("name must be between 1 and 100 characters")
+	}
+	if len(scopes) == 0 {
+		return nil, "", errors.New("at least one scope is required")
+	}
+	seen := make(map[string]bool)
+	granted := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			return nil, "", errors.New("unknown scope: " + scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			granted = append(granted, scope)
+		}
+	}
+	if expiresInDays < 0 || expiresInDays > MaxAPITokenDays {
+		return nil, "", errors.New("expires_in_days must be between 0 (never) and 365")
+	}
+
+	count, err := s.apiTokenRepo.CountByUser(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= MaxAPITokens {
+		return nil, "", errors.New("too many API tokens; revoke one first")
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	secret := APITokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	token := &models.APIToken{
+		UserID:    id,
+		Name:      name,
+		Prefix:    secret[:len(APITokenPrefix)+6],
+		Hash:      hashAPIToken(secret),
+		Scopes:    granted,
+		CreatedAt: now,
+	}
+	if expiresInDays > 0 {
+		token.ExpiresAt = now.AddDate(0, 0, expiresInDays)
+	}
+
+	if err := s.apiTokenRepo.CreateToken(token); err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// GetUserTokens returns a user's tokens, newest first
+func (s *APITokenService) GetUserTokens(userID string) ([]models.APIToken, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	tokens, err := s.apiTokenRepo.FindByUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		tokens = []models.APIToken{}
+	}
+	return tokens, nil
+}
+
+// RevokeToken deletes one of a user's tokens
+func (s *APITokenService) RevokeToken(userID, tokenID string) error {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	tid, err := primitive.ObjectIDFromHex(tokenID)
+	if err != nil {
+		return errors.New("token not found")
+	}
+	return s.apiTokenRepo.DeleteToken(tid, uid)
+}
+
+// AuthenticateToken resolves a token to its user and scopes and records its use.
+// The user is loaded on every request, so role changes and bans apply at once.
+func (s *APITokenService) AuthenticateToken(secret, ip string) (*models.User, []string, error) {
+	if !strings.HasPrefix(secret, APITokenPrefix) {
+		return nil, nil, ErrInvalidAPIToken
+	}
+
+	token, err := s.apiTokenRepo.FindByHash(hashAPIToken(secret))
+	if err != nil {
+		return nil, nil, ErrInvalidAPIToken
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID.Hex())
+	if err != nil {
+		return nil, nil, ErrInvalidAPIToken
+	}
+	if user.Banned {
+		return nil, nil, ErrUserBanned
+	}
+
+	now := time.Now()
+	if now.Sub(token.LastUsedAt) > apiTokenTouchInterval || token.LastUsedIP != ip {
+		s.apiTokenRepo.TouchLastUsed(token.ID, now, ip)
+	}
+	return user, token.Scopes, nil
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/go-ctf-platform/backend/internal/config"
@@ -11,17 +13,36 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// AccessTokenTTL is how long an access token is valid; clients renew it with their refresh token
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a device stays signed in without refreshing; every refresh extends it
+	RefreshTokenTTL = 7 * 24 * time.Hour
+	// twoFactorChallengeTTL is how long a user has to enter their code after the password step
+	twoFactorChallengeTTL = 5 * time.Minute
+)
+
 type AuthService struct {
-	userRepo     *repositories.UserRepository
-	emailService *EmailService
-	config       *config.Config
+	userRepo         *repositories.UserRepository
+	emailService     *EmailService
+	twoFactorService *TwoFactorService
+	sessionService   *SessionService
+	config           *config.Config
 }
 
-func NewAuthService(userRepo *repositories.UserRepository, emailService *EmailService, cfg *config.Config) *AuthService {
+func NewAuthService(
+	userRepo *repositories.UserRepository,
+	emailService *EmailService,
+	twoFactorService *TwoFactorService,
+	sessionService *SessionService,
+	cfg *config.Config,
+) *AuthService {
 	return &AuthService{
-		userRepo:     userRepo,
-		emailService: emailService,
-		config:       cfg,
+		userRepo:         userRepo,
+		emailService:     emailService,
+		twoFactorService: twoFactorService,
+		sessionService:   sessionService,
+		config:           cfg,
 	}
 }
 
@@ -142,41 +163,142 @@ type UserInfo struct {
 	Role     string `json:"role"`
 }
 
-// Login authenticates a user and returns a JWT token and user info
-func (s *AuthService) Login(usernameOrEmail, password string) (string, *UserInfo, error) {
+// LoginResult is the outcome of a login or refresh: an access and refresh token
+// pair, or a challenge token to exchange for one with a two-factor code
+type LoginResult struct {
+	Token             string // Access token
+	RefreshToken      string
+	User              *UserInfo
+	TwoFactorRequired bool
+	ChallengeToken    string
+}
+
+// Login authenticates a user. Users with two-factor authentication enabled get a
+// short-lived challenge token instead of a session; see LoginWithTwoFactor.
+func (s *AuthService) Login(usernameOrEmail, password string, client ClientInfo) (*LoginResult, error) {
 	// Try to find by username first
 	user, err := s.userRepo.FindByUsername(usernameOrEmail)
 	if err != nil {
 		// Try to find by email
 		user, err = s.userRepo.FindByEmail(usernameOrEmail)
 		if err != nil {
-			return "", nil, errors.New("invalid credentials")
+			return nil, errors.New("invalid credentials")
 		}
 	}
 
 	// Check if email is verified
 	if !user.EmailVerified {
-		return "", nil, errors.New("please verify your email before logging in")
+		return nil, errors.New("please verify your email before logging in")
 	}
 
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
+	}
+
+	return s.startLogin(user, client)
+}
+
+// startLogin finishes a login once the user has proven who they are: banned users
+// are rejected and users with two-factor authentication get a challenge instead of a session
+func (s *AuthService) startLogin(user *models.User, client ClientInfo) (*LoginResult, error) {
+	if user.Banned {
+		return nil, ErrUserBanned
+	}
+
+	if user.TwoFactorEnabled {
+		challenge, err := s.issueChallengeToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	return s.newSession(user, false, client)
+}
+
+// LoginWithTwoFactor completes a login with the challenge token from Login and a
+// TOTP or recovery code
+func (s *AuthService) LoginWithTwoFactor(challengeToken, code string, client ClientInfo) (*LoginResult, error) {
+	userID, err := s.parseChallengeToken(challengeToken)
+	if err != nil {
+		return nil, errors.New("invalid or expired login challenge")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.TwoFactorEnabled {
+		return nil, errors.New("invalid or expired login challenge")
+	}
+	if user.Banned {
+		return nil, errors.New("account is banned")
+	}
+
+	if err := s.twoFactorService.VerifyCode(user, code); err != nil {
+		return nil, err
+	}
+
+	return s.newSession(user, true, client)
+}
+
+// newSession starts a server-side session and issues its tokens; twoFactor records
+// that the login used a second factor
+func (s *AuthService) newSession(user *models.User, twoFactor bool, client ClientInfo) (*LoginResult, error) {
+	session, refreshToken, err := s.sessionService.CreateSession(user.ID, twoFactor, client, RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.issueAccessToken(user, session)
+	if err != nil {
+		return nil, err
+	}
+	result.RefreshToken = refreshToken
+	return result, nil
+}
+
+// Refresh rotates a refresh token and issues a new access token, with the user's
+// current role
+func (s *AuthService) Refresh(refreshToken string) (*LoginResult, error) {
+	session, next, err := s.sessionService.RotateRefreshToken(refreshToken, RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID.Hex())
+	if err != nil {
+		s.sessionService.RevokeSession(session.JTI)
+		return nil, ErrSessionRevoked
+	}
+	if user.Banned {
+		s.sessionService.RevokeSession(session.JTI)
+		return nil, ErrUserBanned
+	}
+
+	result, err := s.issueAccessToken(user, session)
+	if err != nil {
+		return nil, err
 	}
+	result.RefreshToken = next
+	return result, nil
+}
 
+// issueAccessToken signs a short-lived access token for a session
+func (s *AuthService) issueAccessToken(user *models.User, session *models.Session) (*LoginResult, error) {
 	// Generate JWT token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  user.ID.Hex(),
-		"username": user.Username,
-		"email":    user.Email,
-		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
+		"user_id":    user.ID.Hex(),
+		"username":   user.Username,
+		"email":      user.Email,
+		"role":       user.Role,
+		"two_factor": session.TwoFactor,
+		"jti":        session.JTI,
+		"exp":        time.Now().Add(AccessTokenTTL).Unix(),
 	})
 
 	tokenString, err := token.SignedString([]byte(s.config.JWTSecret))
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
 	// Create user info response
@@ -187,7 +309,73 @@ func (s *AuthService) Login(usernameOrEmail, password string) (string, *UserInfo
 		Role:     user.Role,
 	}
 
-	return tokenString, userInfo, nil
+	return &LoginResult{Token: tokenString, User: userInfo}, nil
+}
+
+// Logout revokes the session of an access token, which may have expired, or else
+// of a refresh token. Invalid tokens are ignored.
+func (s *AuthService) Logout(accessToken, refreshToken string) error {
+	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.config.JWTSecret), nil
+	}, jwt.WithoutClaimsValidation())
+	if err == nil && token.Valid {
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if jti, _ := claims["jti"].(string); jti != "" {
+				return s.sessionService.RevokeSession(jti)
+			}
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	jti, err := s.sessionService.SessionForRefreshToken(refreshToken)
+	if err != nil {
+		return nil
+	}
+	return s.sessionService.RevokeSession(jti)
+}
+
+// challengeKey signs two-factor challenge tokens. It is derived from, but differs
+// from, the session key so a challenge token can never pass as a session.
+func (s *AuthService) challengeKey() []byte {
+	key := sha256.Sum256([]byte("2fa-challenge:" + s.config.JWTSecret))
+	return key[:]
+}
+
+func (s *AuthService) issueChallengeToken(user *models.User) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID.Hex(),
+		"purpose": "2fa",
+		"exp":     time.Now().Add(twoFactorChallengeTTL).Unix(),
+	})
+	return token.SignedString(s.challengeKey())
+}
+
+// parseChallengeToken validates a challenge token and returns its user ID
+func (s *AuthService) parseChallengeToken(tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.challengeKey(), nil
+	})
+	if err != nil || !token.Valid {
+		return "", errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != "2fa" {
+		return "", errors.New("invalid token")
+	}
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	return userID, nil
 }
 
 // RequestPasswordReset sends a password reset email
@@ -236,11 +424,18 @@ func (s *AuthService) ResetPassword(token, newPassword string) error {
 	user.ResetPasswordToken = ""
 	user.UpdatedAt = time.Now()
 
-	return s.userRepo.UpdateUser(user)
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password may still be signed in
+	_, err = s.sessionService.RevokeAllSessions(user.ID, "")
+	return err
 }
 
-// ChangePassword allows a logged-in user to change their password
-func (s *AuthService) ChangePassword(userID, oldPassword, newPassword string) error {
+// ChangePassword allows a logged-in user to change their password; their other
+// sessions are signed out
+func (s *AuthService) ChangePassword(userID, sessionJTI, oldPassword, newPassword string) error {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return errors.New("user not found")
@@ -261,5 +456,10 @@ func (s *AuthService) ChangePassword(userID, oldPassword, newPassword string) er
 	user.PasswordHash = string(hashedPassword)
 	user.UpdatedAt = time.Now()
 
-	return s.userRepo.UpdateUser(user)
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return err
+	}
+
+	_, err = s.sessionService.RevokeAllSessions(user.ID, sessionJTI)
+	return err
 }
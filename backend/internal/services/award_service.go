@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AwardService struct {
+	awardRepo *repositories.AwardRepository
+	teamRepo  *repositories.TeamRepository
+	userRepo  *repositories.UserRepository
+}
+
+func NewAwardService(
+	awardRepo *repositories.AwardRepository,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+) *AwardService {
+	return &AwardService{
+		awardRepo: awardRepo,
+		teamRepo:  teamRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// AwardInfo is an award with the names of the team, user and admin it refers to
+type AwardInfo struct {
+	ID            string `json:"id"`
+	TeamID        string `json:"team_id,omitempty"`
+	TeamName      string `json:"team_name,omitempty"`
+	UserID        string `json:"user_id,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Points        int    `json:"points"`
+	Reason        string `json:"reason"`
+	CreatedBy     string `json:"created_by"`
+	CreatedByName string `json:"created_by_name"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at,omitempty"`
+}
+
+// validateAward checks the points and reason of an award
+func validateAward(points int, reason string) error {
+	if points == 0 {
+		return errors.New("points must not be zero")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
+
+// CreateAward grants (positive points) or deducts (negative points) points to a
+// team or a user. An award to a user also counts for the team they are in.
+func (s *AwardService) CreateAward(adminID primitive.ObjectID, teamID, userID string, points int, reason string) (*AwardInfo, error) {
+	if err := validateAward(points, reason); err != nil {
+		return nil, err
+	}
+	if (teamID == "") == (userID == "") {
+		return nil, errors.New("specify either a team or a user")
+	}
+
+	award := &models.Award{
+		Points:    points,
+		Reason:    strings.TrimSpace(reason),
+		CreatedBy: adminID,
+	}
+
+	if teamID != "" {
+		team, err := s.teamRepo.FindTeamByID(teamID)
+		if err != nil {
+			return nil, errors.New("team not found")
+		}
+		award.TeamID = team.ID
+	} else {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		award.UserID = user.ID
+		if team, err := s.teamRepo.FindTeamByMemberID(userID); err == nil {
+			award.TeamID = team.ID
+		}
+	}
+
+	if err := s.awardRepo.CreateAward(award); err != nil {
+		return nil, err
+	}
+	invalidateScoreboardCache()
+
+	return s.toInfo(award, newNameCache()), nil
+}
+
+// UpdateAward changes the points and reason of an award
+func (s *AwardService) UpdateAward(id string, points int, reason string) (*AwardInfo, error) {
+	if err := validateAward(points, reason); err != nil {
+		return nil, err
+	}
+
+	award, err := s.awardRepo.GetAwardByID(id)
+	if err != nil {
+		return nil, errors.New("award not found")
+	}
+	award.Points = points
+	award.Reason = strings.TrimSpace(reason)
+
+	if err := s.awardRepo.UpdateAward(award); err != nil {
+		return nil, err
+	}
+	invalidateScoreboardCache()
+
+	return s.toInfo(award, newNameCache()), nil
+}
+
+// DeleteAward removes an award, reverting its effect on the scoreboard
+func (s *AwardService) DeleteAward(id string) error {
+	if _, err := s.awardRepo.GetAwardByID(id); err != nil {
+		return errors.New("award not found")
+	}
+	if err := s.awardRepo.DeleteAward(id); err != nil {
+		return err
+	}
+	invalidateScoreboardCache()
+	return nil
+}
+
+// GetAward returns a single award
+func (s *AwardService) GetAward(id string) (*AwardInfo, error) {
+	award, err := s.awardRepo.GetAwardByID(id)
+	if err != nil {
+		return nil, errors.New("award not found")
+	}
+	return s.toInfo(award, newNameCache()), nil
+}
+
+// GetAllAwards returns every award, newest first (admin view)
+func (s *AwardService) GetAllAwards() ([]AwardInfo, error) {
+	awards, err := s.awardRepo.GetAllAwards()
+	if err != nil {
+		return nil, err
+	}
+	return s.toInfos(awards), nil
+}
+
+// GetTeamAwards returns the awards of a team; only its members and admins may see them
+func (s *AwardService) GetTeamAwards(teamID, userID string, isAdmin bool) ([]AwardInfo, error) {
+	team, err := s.teamRepo.FindTeamByID(teamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+
+	if !isAdmin {
+		member := false
+		for _, mid := range team.MemberIDs {
+			if mid.Hex() == userID {
+				member = true
+				break
+			}
+		}
+		if !member {
+			return nil, errors.New("only team members can view the team's awards")
+		}
+	}
+
+	awards, err := s.awardRepo.GetTeamAwards(team.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toInfos(awards), nil
+}
+
+// nameCache avoids looking up the same team or user twice while listing awards
+type nameCache struct {
+	teams map[primitive.ObjectID]string
+	users map[primitive.ObjectID]string
+}
+
+func newNameCache() *nameCache {
+	return &nameCache{
+		teams: make(map[primitive.ObjectID]string),
+		users: make(map[primitive.ObjectID]string),
+	}
+}
+
+func (s *AwardService) teamName(cache *nameCache, id primitive.ObjectID) string {
+	if name, ok := cache.teams[id]; ok {
+		return name
+	}
+	cache.teams[id] = ""
+	if team, err := s.teamRepo.FindTeamByID(id.Hex()); err == nil {
+		cache.teams[id] = team.Name
+	}
+	return cache.teams[id]
+}
+
+func (s *AwardService) username(cache *nameCache, id primitive.ObjectID) string {
+	if name, ok := cache.users[id]; ok {
+		return name
+	}
+	cache.users[id] = "Unknown"
+	if user, err := s.userRepo.FindByID(id.Hex()); err == nil {
+		cache.users[id] = user.Username
+	}
+	return cache.users[id]
+}
+
+func (s *AwardService) toInfos(awards []models.Award) []AwardInfo {
+	cache := newNameCache()
+	infos := make([]AwardInfo, 0, len(awards))
+	for i := range awards {
+		infos = append(infos, *s.toInfo(&awards[i], cache))
+	}
+	return infos
+}
+
+func (s *AwardService) toInfo(award *models.Award, cache *nameCache) *AwardInfo {
+	info := &AwardInfo{
+		ID:            award.ID.Hex(),
+		Points:        award.Points,
+		Reason:        award.Reason,
+		CreatedBy:     award.CreatedBy.Hex(),
+		CreatedByName: s.username(cache, award.CreatedBy),
+		CreatedAt:     award.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if !award.TeamID.IsZero() {
+		info.TeamID = award.TeamID.Hex()
+		info.TeamName = s.teamName(cache, award.TeamID)
+	}
+	if !award.UserID.IsZero() {
+		info.UserID = award.UserID.Hex()
+		info.Username = s.username(cache, award.UserID)
+	}
+	if !award.UpdatedAt.IsZero() {
+		info.UpdatedAt = award.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return info
+}
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/go-ctf-platform/backend/internal/ctfcli"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChallengeImportService imports and exports challenges as ctfcli challenge.yml bundles
+type ChallengeImportService struct {
+	challengeService *ChallengeService
+	challengeRepo    *repositories.ChallengeRepository
+	fileService      *FileService
+}
+
+func NewChallengeImportService(
+	challengeService *ChallengeService,
+	challengeRepo *repositories.ChallengeRepository,
+	fileService *FileService,
+) *ChallengeImportService {
+	return &ChallengeImportService{
+		challengeService: challengeService,
+		challengeRepo:    challengeRepo,
+		fileService:      fileService,
+	}
+}
+
+// ImportResult lists the slugs of the challenges an import created and updated
+type ImportResult struct {
+	Created       []string `json:"created"`
+	Updated       []string `json:"updated"`
+	FilesUploaded int      `json:"files_uploaded"`
+	FilesRemoved  int      `json:"files_removed"`
+}
+
+// importSpec is a parsed challenge.yml and the directory it was found in
+type importSpec struct {
+	dir  string
+	spec *ctfcli.Challenge
+}
+
+// findSpecs walks the bundle for challenge.yml files and parses them
+func findSpecs(fsys fs.FS) ([]importSpec, error) {
+	var specs []importSpec
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if (strings.HasPrefix(d.Name(), ".") && p != ".") || d.Name() == "__MACOSX" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != ctfcli.FileName && d.Name() != ctfcli.FileNameAlt {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		spec, err := ctfcli.Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		specs = append(specs, importSpec{dir: path.Dir(p), spec: spec})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, errors.New("no challenge.yml found")
+	}
+	return specs, nil
+}
+
+// orderByRequirements puts challenges after the challenges of the bundle they require,
+// so prerequisites exist by the time they are referenced. Cycles keep their file order
+// and are rejected when the prerequisites are validated.
+func orderByRequirements(specs []importSpec) []importSpec {
+	index := make(map[string]int)
+	for i, s := range specs {
+		index[s.spec.Slug] = i
+		if _, taken := index[s.spec.Name]; !taken {
+			index[s.spec.Name] = i
+		}
+	}
+
+	ordered := make([]importSpec, 0, len(specs))
+	state := make(map[int]int) // 1 = visiting, 2 = done
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != 0 {
+			return
+		}
+		state[i] = 1
+		if req := specs[i].spec.Requirements; req != nil {
+			for _, name := range req.Prerequisites {
+				if j, ok := index[name]; ok {
+					visit(j)
+				}
+			}
+		}
+		state[i] = 2
+		ordered = append(ordered, specs[i])
+	}
+	for i := range specs {
+		visit(i)
+	}
+	return ordered
+}
+
+// Import creates or updates every challenge.yml challenge found in fsys. Challenges
+// are matched by slug (the slug key, or the slugified name), falling back to an
+// unslugged challenge with the same title, so importing the same bundle again
+// updates the challenges instead of duplicating them. Bundled files are uploaded
+// as attachments; attachments no longer listed are removed.
+func (s *ChallengeImportService) Import(fsys fs.FS) (*ImportResult, error) {
+	specs, err := findSpecs(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]string)
+	for _, is := range specs {
+		if is.spec.Slug == "" {
+			is.spec.Slug = models.Slugify(is.spec.Name)
+		} else {
+			is.spec.Slug = models.Slugify(is.spec.Slug)
+		}
+		if is.spec.Slug == "" {
+			return nil, fmt.Errorf("%s: cannot derive a slug from the name", is.dir)
+		}
+		if other, dup := seen[is.spec.Slug]; dup {
+			return nil, fmt.Errorf("%s and %s share the slug %q", other, is.dir, is.spec.Slug)
+		}
+		seen[is.spec.Slug] = is.dir
+	}
+
+	result := &ImportResult{Created: []string{}, Updated: []string{}}
+	for _, is := range orderByRequirements(specs) {
+		if err := s.importOne(fsys, is, result); err != nil {
+			return result, fmt.Errorf("%s: %w", is.spec.Slug, err)
+		}
+	}
+	return result, nil
+}
+
+// findExisting returns the challenge an import updates, or nil if it creates a new one
+func (s *ChallengeImportService) findExisting(spec *ctfcli.Challenge) (*models.Challenge, error) {
+	if existing, err := s.challengeRepo.GetChallengeBySlug(spec.Slug); err == nil {
+		return existing, nil
+	}
+
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return nil, err
+	}
+	for i := range challenges {
+		if challenges[i].Slug == "" && challenges[i].Title == spec.Name {
+			return &challenges[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveRequirements maps prerequisite slugs or names to challenge IDs
+func (s *ChallengeImportService) resolveRequirements(spec *ctfcli.Challenge) ([]primitive.ObjectID, error) {
+	if spec.Requirements == nil || len(spec.Requirements.Prerequisites) == 0 {
+		return nil, nil
+	}
+
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(spec.Requirements.Prerequisites))
+	for _, name := range spec.Requirements.Prerequisites {
+		found := false
+		for _, c := range challenges {
+			if c.Slug == name || c.Title == name {
+				ids = append(ids, c.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("required challenge not found: %s", name)
+		}
+	}
+	return ids, nil
+}
+
+func (s *ChallengeImportService) importOne(fsys fs.FS, is importSpec, result *ImportResult) error {
+	challenge, err := is.spec.ToChallenge()
+	if err != nil {
+		return err
+	}
+	for _, h := range challenge.Hints {
+		if h.Cost < 0 {
+			return errors.New("hint costs cannot be negative")
+		}
+	}
+
+	challenge.Prerequisites, err = s.resolveRequirements(is.spec)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.findExisting(is.spec)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		for i := range challenge.Hints {
+			challenge.Hints[i].ID = primitive.NewObjectID()
+		}
+		if err := s.challengeService.CreateChallenge(challenge); err != nil {
+			return err
+		}
+		result.Created = append(result.Created, challenge.Slug)
+	} else {
+		// Keep hint IDs by position so earlier unlocks stay valid
+		for i := range challenge.Hints {
+			challenge.Hints[i].ID = primitive.NewObjectID()
+			for _, old := range existing.Hints {
+				if old.Order == challenge.Hints[i].Order {
+					challenge.Hints[i].ID = old.ID
+					break
+				}
+			}
+		}
+		if err := s.challengeService.UpdateChallenge(existing.ID.Hex(), challenge); err != nil {
+			return err
+		}
+		challenge.ID = existing.ID
+		challenge.Attachments = existing.Attachments
+		result.Updated = append(result.Updated, challenge.Slug)
+	}
+
+	return s.syncFiles(fsys, is, challenge, result)
+}
+
+// syncFiles uploads the bundled files that are new or changed and removes
+// attachments the challenge.yml no longer lists
+func (s *ChallengeImportService) syncFiles(fsys fs.FS, is importSpec, challenge *models.Challenge, result *ImportResult) error {
+	listed := make(map[string]bool)
+	for _, file := range is.spec.Files {
+		if ctfcli.IsURL(file) {
+			continue
+		}
+
+		p := path.Join(is.dir, file)
+		info, err := fs.Stat(fsys, p)
+		if err != nil {
+			return fmt.Errorf("file %s: %w", file, err)
+		}
+		if info.Size() > s.fileService.MaxSize() {
+			return fmt.Errorf("file %s exceeds maximum upload size", file)
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("file %s: %w", file, err)
+		}
+		name := sanitizeFileName(file)
+		listed[name] = true
+
+		hash := sha256.Sum256(data)
+		var current *models.ChallengeFile
+		for i := range challenge.Attachments {
+			if challenge.Attachments[i].Name == name {
+				current = &challenge.Attachments[i]
+				break
+			}
+		}
+		if current != nil && current.SHA256 == hex.EncodeToString(hash[:]) {
+			continue
+		}
+		if current != nil {
+			if err := s.fileService.DeleteFile(challenge.ID.Hex(), current.ID.Hex()); err != nil {
+				return err
+			}
+			result.FilesRemoved++
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		_, err = s.fileService.UploadFile(challenge.ID.Hex(), name, contentType, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("file %s: %w", file, err)
+		}
+		result.FilesUploaded++
+	}
+
+	for _, att := range challenge.Attachments {
+		if listed[att.Name] {
+			continue
+		}
+		if err := s.fileService.DeleteFile(challenge.ID.Hex(), att.ID.Hex()); err != nil {
+			return err
+		}
+		result.FilesRemoved++
+	}
+	return nil
+}
+
+// Export writes every challenge as <slug>/challenge.yml, with its attachments
+// under <slug>/dist/, to a zip archive
+func (s *ChallengeImportService) Export(w io.Writer) error {
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return err
+	}
+
+	// Challenges created in the dashboard have no slug yet; derive a unique one
+	slugs := make(map[primitive.ObjectID]string)
+	used := make(map[string]bool)
+	for _, c := range challenges {
+		if c.Slug != "" {
+			slugs[c.ID] = c.Slug
+			used[c.Slug] = true
+		}
+	}
+	for _, c := range challenges {
+		if c.Slug != "" {
+			continue
+		}
+		base := models.Slugify(c.Title)
+		if base == "" {
+			base = "challenge"
+		}
+		slug := base
+		for i := 2; used[slug]; i++ {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		slugs[c.ID] = slug
+		used[slug] = true
+	}
+
+	zw := zip.NewWriter(w)
+	for i := range challenges {
+		c := &challenges[i]
+		slug := slugs[c.ID]
+
+		prerequisites := make([]string, 0, len(c.Prerequisites))
+		for _, id := range c.Prerequisites {
+			if p, ok := slugs[id]; ok {
+				prerequisites = append(prerequisites, p)
+			}
+		}
+
+		spec := ctfcli.FromChallenge(c, slug, prerequisites)
+		data, err := ctfcli.Marshal(spec)
+		if err != nil {
+			return err
+		}
+		fw, err := zw.Create(slug + "/" + ctfcli.FileName)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+
+		for j, att := range c.Attachments {
+			if err := s.exportFile(zw, c, &att, slug+"/"+spec.Files[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+func (s *ChallengeImportService) exportFile(zw *zip.Writer, c *models.Challenge, att *models.ChallengeFile, name string) error {
+	_, reader, err := s.fileService.OpenFile(c.ID.Hex(), att.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer reader.Close()
+
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, reader)
+	return err
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/realtime"
+)
+
+// ReleaseCheckInterval is how often WatchReleases looks for scheduled challenges that went live
+const ReleaseCheckInterval = 15 * time.Second
+
+// ReleaseEvent is streamed to clients when challenges become visible to players
+type ReleaseEvent struct {
+	Challenges []ReleasedChallenge `json:"challenges"`
+}
+
+// ReleasedChallenge identifies a newly visible challenge; title and category are
+// empty for challenges hidden while locked
+type ReleasedChallenge struct {
+	ChallengeID string `json:"challenge_id"`
+	Title       string `json:"title"`
+	Category    string `json:"category"`
+}
+
+// publishReleases streams a release event for challenges that just became visible
+func (s *ChallengeService) publishReleases(challenges []models.Challenge) {
+	event := ReleaseEvent{Challenges: make([]ReleasedChallenge, 0, len(challenges))}
+	for _, c := range challenges {
+		released := ReleasedChallenge{ChallengeID: c.ID.Hex()}
+		// The stream is public; challenges hidden while locked keep their title secret
+		if !c.HiddenWhileLocked() {
+			released.Title = c.Title
+			released.Category = c.Category
+		}
+		event.Challenges = append(event.Challenges, released)
+	}
+
+	if err := s.broker.Publish(realtime.EventRelease, event); err != nil {
+		log.Printf("Warning: failed to publish release event: %v", err)
+	}
+}
+
+// claimRelease reports whether this node should announce a scheduled release. With
+// Redis, only the first node to claim it does, so clients are not notified once per node.
+func claimRelease(c *models.Challenge) bool {
+	if database.RDB == nil {
+		return true
+	}
+	key := fmt.Sprintf("challenge_release:%s:%d", c.ID.Hex(), c.ReleaseAt.Unix())
+	claimed, err := database.RDB.SetNX(context.Background(), key, 1, time.Hour).Result()
+	if err != nil {
+		// Announcing twice is better than not at all
+		return true
+	}
+	return claimed
+}
+
+// releaseDue invalidates the cached scoreboards and notifies clients about the
+// scheduled challenges released in (since, now]
+func (s *ChallengeService) releaseDue(since, now time.Time) error {
+	challenges, err := s.challengeRepo.GetChallengesReleasedBetween(since, now)
+	if err != nil {
+		return err
+	}
+	if len(challenges) == 0 {
+		return nil
+	}
+
+	invalidateScoreboardCache()
+
+	var claimed []models.Challenge
+	for i := range challenges {
+		if claimRelease(&challenges[i]) {
+			claimed = append(claimed, challenges[i])
+		}
+	}
+	if len(claimed) > 0 {
+		s.publishReleases(claimed)
+	}
+	return nil
+}
+
+// WatchReleases checks for scheduled challenges going live every interval.
+// Call this in a goroutine during application startup.
+func (s *ChallengeService) WatchReleases(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := time.Now()
+	for now := range ticker.C {
+		if err := s.releaseDue(since, now); err != nil {
+			log.Printf("Warning: failed to check challenge releases: %v", err)
+			continue
+		}
+		since = now
+	}
+}
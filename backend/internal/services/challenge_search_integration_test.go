@@ -0,0 +1,187 @@
+//go:build integration
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// searchFixture holds challenges covering the search and filter cases; only
+// "Baby RSA" is unlocked for a player without solves
+type searchFixture struct {
+	s       *ChallengeService
+	player  *ChallengeAccess
+	byTitle map[string]*models.Challenge
+}
+
+func newSearchFixture(t *testing.T) *searchFixture {
+	t.Helper()
+
+	s := newTestChallengeService(t)
+	if err := repositories.NewChallengeRepository().EnsureIndexes(); err != nil {
+		t.Fatal(err)
+	}
+	f := &searchFixture{s: s, byTitle: map[string]*models.Challenge{}}
+
+	create := func(c *models.Challenge) *models.Challenge {
+		t.Helper()
+		flag, err := models.NewFlag(models.FlagTypeStatic, "flag{"+c.Title+"}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Difficulty = "easy"
+		c.MinPoints = c.MaxPoints
+		c.Scoring = models.ScoringStatic
+		c.Flags = []models.Flag{flag}
+		if err := s.CreateChallenge(c); err != nil {
+			t.Fatal(err)
+		}
+		f.byTitle[c.Title] = c
+		return c
+	}
+
+	baby := create(&models.Challenge{Title: "Baby RSA", Category: "crypto", Tags: []string{"rsa"}, MaxPoints: 100,
+		Description: "Factor the small modulus"})
+	create(&models.Challenge{Title: "Hard RSA", Category: "crypto", Tags: []string{"rsa"}, MaxPoints: 300,
+		Description: "The modulus hides a secret", Prerequisites: []primitive.ObjectID{baby.ID}})
+	create(&models.Challenge{Title: "Secret Stage", Category: "misc", MaxPoints: 500,
+		Description: "Another modulus", Prerequisites: []primitive.ObjectID{baby.ID}, HideLocked: true})
+	create(&models.Challenge{Title: "Web Warmup", Category: "web", Tags: []string{"beginner"}, MaxPoints: 50,
+		Description: "Look at the source"})
+	create(&models.Challenge{Title: "Draft Modulus", Category: "crypto", MaxPoints: 200,
+		Description: "Work in progress modulus", Visibility: models.VisibilityDraft})
+
+	access, err := s.GetChallengeAccess(createTestUsers(t, 1)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.player = access
+	return f
+}
+
+// find runs the filter as a player would see it, or as an admin when player is false
+func (f *searchFixture) find(t *testing.T, filter repositories.ChallengeFilter, player bool) []string {
+	t.Helper()
+
+	var access *ChallengeAccess
+	if player {
+		access = f.player
+		filter.ReleasedAt = time.Now()
+		if filter.Solved != nil {
+			filter.SolvedIDs = access.SolvedIDs()
+		}
+	}
+	challenges, err := f.s.FindChallenges(filter, access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := make([]string, len(challenges))
+	for i, c := range challenges {
+		titles[i] = c.Title
+	}
+	return titles
+}
+
+func assertTitles(t *testing.T, got []string, want ...string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("got %q, want %q", got, want)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %q, want %q", got, want)
+			return
+		}
+	}
+}
+
+func TestFindChallengesSearchSkipsLockedDescriptions(t *testing.T) {
+	setupTestDB(t)
+	f := newSearchFixture(t)
+
+	// Only the unlocked challenge matches on its description
+	got := f.find(t, repositories.ChallengeFilter{Search: "modulus", Sort: repositories.ChallengeSortTitle}, true)
+	assertTitles(t, got, "Baby RSA")
+
+	// Locked challenges still match on what players can see of them
+	got = f.find(t, repositories.ChallengeFilter{Search: "rsa", Sort: repositories.ChallengeSortTitle}, true)
+	assertTitles(t, got, "Baby RSA", "Hard RSA")
+	got = f.find(t, repositories.ChallengeFilter{Search: "stage"}, true)
+	assertTitles(t, got, "Secret Stage")
+
+	// Admins search every description, drafts included
+	got = f.find(t, repositories.ChallengeFilter{Search: "modulus", Sort: repositories.ChallengeSortTitle}, false)
+	assertTitles(t, got, "Baby RSA", "Draft Modulus", "Hard RSA", "Secret Stage")
+}
+
+func TestFindChallengesSearchUnlocksDescriptionsWithSolves(t *testing.T) {
+	setupTestDB(t)
+	f := newSearchFixture(t)
+
+	user := createTestUsers(t, 1)[0]
+	if _, err := f.s.SubmitFlag(user, f.byTitle["Baby RSA"].ID.Hex(), "flag{Baby RSA}"); err != nil {
+		t.Fatal(err)
+	}
+	access, err := f.s.GetChallengeAccess(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.player = access
+
+	got := f.find(t, repositories.ChallengeFilter{Search: "modulus", Sort: repositories.ChallengeSortTitle}, true)
+	assertTitles(t, got, "Baby RSA", "Hard RSA", "Secret Stage")
+}
+
+func TestFindChallengesFiltersAndSorts(t *testing.T) {
+	setupTestDB(t)
+	f := newSearchFixture(t)
+
+	tests := []struct {
+		name   string
+		filter repositories.ChallengeFilter
+		want   []string
+	}{
+		{
+			name:   "category by points",
+			filter: repositories.ChallengeFilter{Category: "crypto", Sort: repositories.ChallengeSortPoints, Descending: true},
+			want:   []string{"Hard RSA", "Baby RSA"},
+		},
+		{
+			name:   "tag and search",
+			filter: repositories.ChallengeFilter{Tags: []string{"rsa"}, Search: "hard"},
+			want:   []string{"Hard RSA"},
+		},
+		{
+			name:   "tag excludes other categories",
+			filter: repositories.ChallengeFilter{Tags: []string{"beginner"}, Category: "crypto"},
+			want:   []string{},
+		},
+		{
+			name:   "category then title",
+			filter: repositories.ChallengeFilter{Sort: repositories.ChallengeSortCategory},
+			want:   []string{"Baby RSA", "Hard RSA", "Secret Stage", "Web Warmup"},
+		},
+		{
+			name:   "title descending",
+			filter: repositories.ChallengeFilter{Sort: repositories.ChallengeSortTitle, Descending: true},
+			want:   []string{"Web Warmup", "Secret Stage", "Hard RSA", "Baby RSA"},
+		},
+		{
+			name:   "unsolved by points",
+			filter: repositories.ChallengeFilter{Solved: new(bool), Sort: repositories.ChallengeSortPoints},
+			want:   []string{"Web Warmup", "Baby RSA", "Hard RSA", "Secret Stage"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertTitles(t, f.find(t, tt.filter, true), tt.want...)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms(`Baby "rsa modulus" -web`)
+	want := []string{"baby", "rsa", "modulus"}
+	if len(got) != len(want) {
+		t.Fatalf("searchTerms = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("searchTerms = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestMatchesVisibleFields(t *testing.T) {
+	challenge := &models.Challenge{
+		Title:       "Hard RSA",
+		Category:    "crypto",
+		Tags:        []string{"number-theory"},
+		Description: "The modulus hides a secret",
+	}
+
+	tests := []struct {
+		search string
+		want   bool
+	}{
+		{"rsa", true},
+		{"HARD", true},
+		{"cryptography", true}, // Prefix of the term, like a stemmed match
+		{"theory", true},
+		{"modulus", false}, // Only in the description
+		{"secret", false},
+		{"-rsa", false},
+		{"web modulus", false},
+		{"web rsa", true},
+	}
+	for _, tt := range tests {
+		if got := matchesVisibleFields(challenge, searchTerms(tt.search)); got != tt.want {
+			t.Errorf("matchesVisibleFields(%q) = %v, want %v", tt.search, got, tt.want)
+		}
+	}
+}
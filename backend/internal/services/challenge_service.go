@@ -1,73 +1,566 @@
 package services
 
 import (
-	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
 
-	"github.com/go-ctf-platform/backend/internal/database"
 	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/realtime"
 	"github.com/go-ctf-platform/backend/internal/repositories"
 	"github.com/go-ctf-platform/backend/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type ChallengeService struct {
 	challengeRepo  *repositories.ChallengeRepository
 	submissionRepo *repositories.SubmissionRepository
 	teamRepo       *repositories.TeamRepository
+	fileService    *FileService
+	userRepo       *repositories.UserRepository
+	cheatRepo      *repositories.CheatReportRepository
+	hintUnlockRepo *repositories.HintUnlockRepository
+	solveRepo      *repositories.SolveRepository
+	awardRepo      *repositories.AwardRepository
+	eventService   *EventService
+	broker         realtime.Broker
 }
 
+// ErrChallengeLocked is returned when a challenge's unlock requirements are not met
+var ErrChallengeLocked = errors.New("challenge is locked")
+
+// ErrChallengeArchived is returned when a flag or hint unlock is submitted for an archived challenge
+var ErrChallengeArchived = errors.New("challenge is archived")
+
+// ErrUserBanned is returned when a banned user submits a flag
+var ErrUserBanned = errors.New("account is banned")
+
 func NewChallengeService(
 	challengeRepo *repositories.ChallengeRepository,
 	submissionRepo *repositories.SubmissionRepository,
 	teamRepo *repositories.TeamRepository,
+	fileService *FileService,
+	userRepo *repositories.UserRepository,
+	cheatRepo *repositories.CheatReportRepository,
+	hintUnlockRepo *repositories.HintUnlockRepository,
+	solveRepo *repositories.SolveRepository,
+	awardRepo *repositories.AwardRepository,
+	eventService *EventService,
+	broker realtime.Broker,
 ) *ChallengeService {
 	return &ChallengeService{
 		challengeRepo:  challengeRepo,
 		submissionRepo: submissionRepo,
 		teamRepo:       teamRepo,
+		fileService:    fileService,
+		userRepo:       userRepo,
+		cheatRepo:      cheatRepo,
+		hintUnlockRepo: hintUnlockRepo,
+		solveRepo:      solveRepo,
+		awardRepo:      awardRepo,
+		eventService:   eventService,
+		broker:         broker,
+	}
+}
+
+// prepareFlag validates the flag mode and generates a dynamic flag secret when needed
+func (s *ChallengeService) prepareFlag(challenge *models.Challenge) error {
+	if challenge.FlagMode == "" {
+		challenge.FlagMode = models.FlagModeStatic
+	}
+
+	switch challenge.FlagMode {
+	case models.FlagModeStatic:
+		if len(challenge.StaticFlags()) == 0 {
+			return errors.New("at least one flag is required for static challenges")
+		}
+	case models.FlagModeDynamic:
+		if challenge.FlagSecret == "" {
+			secret, err := utils.GenerateFlagSecret()
+			if err != nil {
+				return err
+			}
+			challenge.FlagSecret = secret
+		}
+	default:
+		return errors.New("invalid flag mode")
+	}
+	return nil
+}
+
+// validatePrerequisites checks that every prerequisite exists and that the
+// dependency graph stays acyclic once the challenge is saved with them
+func (s *ChallengeService) validatePrerequisites(challenge *models.Challenge) error {
+	if challenge.MinTeamScore < 0 {
+		return errors.New("minimum team score cannot be negative")
+	}
+	if len(challenge.Prerequisites) == 0 {
+		return nil
+	}
+
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return err
+	}
+
+	graph := make(map[primitive.ObjectID][]primitive.ObjectID)
+	for _, c := range challenges {
+		graph[c.ID] = c.Prerequisites
+	}
+
+	seen := make(map[primitive.ObjectID]bool)
+	prerequisites := make([]primitive.ObjectID, 0, len(challenge.Prerequisites))
+	for _, id := range challenge.Prerequisites {
+		if id == challenge.ID {
+			return errors.New("a challenge cannot be its own prerequisite")
+		}
+		if _, exists := graph[id]; !exists {
+			return errors.New("prerequisite challenge not found: " + id.Hex())
+		}
+		if !seen[id] {
+			seen[id] = true
+			prerequisites = append(prerequisites, id)
+		}
+	}
+	challenge.Prerequisites = prerequisites
+
+	// A new challenge cannot be anyone's prerequisite yet, so it cannot close a cycle
+	if challenge.ID.IsZero() {
+		return nil
+	}
+	graph[challenge.ID] = prerequisites
+
+	// Walk the prerequisites; reaching the challenge itself again means a cycle
+	visited := make(map[primitive.ObjectID]bool)
+	stack := append([]primitive.ObjectID{}, prerequisites...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == challenge.ID {
+			return errors.New("prerequisites would create a dependency cycle")
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, graph[id]...)
+	}
+	return nil
+}
+
+// validateScoring defaults the scoring function to quadratic, rejects unknown ones and
+// checks the points settings the function uses: static challenges ignore the minimum
+// and decay, decaying ones need a minimum no higher than the maximum and a decay
+func validateScoring(challenge *models.Challenge) error {
+	if challenge.Scoring == "" {
+		challenge.Scoring = models.ScoringQuadratic
+	}
+	if !models.IsValidScoringFunction(challenge.Scoring) {
+		return errors.New("invalid scoring function")
+	}
+	if challenge.MaxPoints < 0 || challenge.MinPoints < 0 || challenge.Decay < 0 {
+		return errors.New("points and decay cannot be negative")
+	}
+	if challenge.Scoring == models.ScoringStatic {
+		return nil
+	}
+	if challenge.MinPoints > challenge.MaxPoints {
+		return errors.New("minimum points cannot exceed maximum points")
 	}
+	if challenge.Decay == 0 {
+		return errors.New("decay is required for " + challenge.Scoring + " scoring")
+	}
+	return nil
+}
+
+// normalizeSlug rewrites a provided slug into its canonical form
+func normalizeSlug(challenge *models.Challenge) error {
+	if challenge.Slug == "" {
+		return nil
+	}
+	challenge.Slug = models.Slugify(challenge.Slug)
+	if challenge.Slug == "" {
+		return errors.New("slug must contain letters or digits")
+	}
+	return nil
 }
 
-func (s *ChallengeService) invalidateScoreboardCache() {
-	if database.RDB != nil {
-		ctx := context.Background()
-		database.RDB.Del(ctx, "scoreboard")
-		database.RDB.Del(ctx, "team_scoreboard")
+// normalizeTags trims tags and drops empty and duplicate ones
+func normalizeTags(challenge *models.Challenge) {
+	seen := make(map[string]bool)
+	tags := make([]string, 0, len(challenge.Tags))
+	for _, tag := range challenge.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	challenge.Tags = tags
+}
+
+// slugError reports a duplicate slug in a readable way
+func slugError(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return errors.New("another challenge already uses this slug")
+	}
+	return err
+}
+
+// validateBloodBonuses checks the first, second and third blood bonuses
+func validateBloodBonuses(challenge *models.Challenge) error {
+	if len(challenge.BloodBonuses) > models.MaxBloodBonuses {
+		return errors.New("at most 3 blood bonuses (first, second and third blood) can be set")
+	}
+	for _, bonus := range challenge.BloodBonuses {
+		if bonus < 0 {
+			return errors.New("blood bonuses cannot be negative")
+		}
+	}
+	return nil
+}
+
+// validateVisibility defaults the visibility to visible and rejects unknown states
+func validateVisibility(challenge *models.Challenge) error {
+	if challenge.Visibility == "" {
+		challenge.Visibility = models.VisibilityVisible
+	}
+	if !models.IsValidVisibility(challenge.Visibility) {
+		return errors.New("visibility must be draft, hidden, visible or archived")
 	}
+	return nil
 }
 
 func (s *ChallengeService) CreateChallenge(challenge *models.Challenge) error {
+	if err := s.prepareFlag(challenge); err != nil {
+		return err
+	}
+	if err := validateBloodBonuses(challenge); err != nil {
+		return err
+	}
+	if err := validateScoring(challenge); err != nil {
+		return err
+	}
+	if err := s.validatePrerequisites(challenge); err != nil {
+		return err
+	}
+	if err := normalizeSlug(challenge); err != nil {
+		return err
+	}
+	if err := validateVisibility(challenge); err != nil {
+		return err
+	}
+	normalizeTags(challenge)
+
 	err := s.challengeRepo.CreateChallenge(challenge)
 	if err == nil {
-		s.invalidateScoreboardCache()
+		invalidateScoreboardCache()
+		if challenge.IsReleased(time.Now()) {
+			s.publishReleases([]models.Challenge{*challenge})
+		}
 	}
-	return err
+	return slugError(err)
 }
 
 func (s *ChallengeService) GetAllChallenges() ([]models.Challenge, error) {
 	return s.challengeRepo.GetAllChallenges()
 }
 
+// FindChallenges returns the challenges matching the filter. Sorting by points
+// orders by the current value, which is derived from the solve count, so the
+// query's order by maximum points is refined here. With a player's access, a
+// search only matches challenges they have not unlocked by what the player can
+// see of them, not by their description.
+func (s *ChallengeService) FindChallenges(filter repositories.ChallengeFilter, access *ChallengeAccess) ([]models.Challenge, error) {
+	challenges, err := s.challengeRepo.FindChallenges(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Search != "" && access != nil {
+		terms := searchTerms(filter.Search)
+		matched := challenges[:0]
+		for _, challenge := range challenges {
+			if access.IsUnlocked(&challenge) || matchesVisibleFields(&challenge, terms) {
+				matched = append(matched, challenge)
+			}
+		}
+		challenges = matched
+	}
+
+	if filter.Sort == repositories.ChallengeSortPoints {
+		sort.SliceStable(challenges, func(i, j int) bool {
+			if filter.Descending {
+				return challenges[i].CurrentPoints() > challenges[j].CurrentPoints()
+			}
+			return challenges[i].CurrentPoints() < challenges[j].CurrentPoints()
+		})
+	}
+	return challenges, nil
+}
+
+// searchTerms splits a text search into its lowercase words, leaving out the
+// words it excludes with a leading dash
+func searchTerms(search string) []string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(strings.ReplaceAll(search, "\"", " "))) {
+		if !strings.HasPrefix(word, "-") {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// matchesVisibleFields reports whether a search term matches a word of the title,
+// category or tags. Words match when one is a prefix of the other, which stands in
+// for the stemming of the text index.
+func matchesVisibleFields(challenge *models.Challenge, terms []string) bool {
+	fields := append([]string{challenge.Title, challenge.Category}, challenge.Tags...)
+	for _, field := range fields {
+		for _, word := range strings.FieldsFunc(strings.ToLower(field), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			for _, term := range terms {
+				if strings.HasPrefix(word, term) || strings.HasPrefix(term, word) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func (s *ChallengeService) GetChallengeByID(id string) (*models.Challenge, error) {
 	return s.challengeRepo.GetChallengeByID(id)
 }
 
 func (s *ChallengeService) UpdateChallenge(id string, challenge *models.Challenge) error {
-	err := s.challengeRepo.UpdateChallenge(id, challenge)
+	existing, err := s.challengeRepo.GetChallengeByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Keep the stored flags and dynamic secret unless new ones are provided
+	if len(challenge.Flags) == 0 {
+		challenge.Flags = existing.Flags
+		challenge.FlagHash = existing.FlagHash
+	}
+	if challenge.FlagSecret == "" {
+		challenge.FlagSecret = existing.FlagSecret
+	}
+	if challenge.Scoring == "" {
+		challenge.Scoring = existing.Scoring
+	}
+	if challenge.Slug == "" {
+		challenge.Slug = existing.Slug
+	}
+	if challenge.Visibility == "" {
+		challenge.Visibility = existing.Visibility
+	}
+	if err := s.prepareFlag(challenge); err != nil {
+		return err
+	}
+	challenge.ID = existing.ID
+	if err := s.validatePrerequisites(challenge); err != nil {
+		return err
+	}
+	if err := validateBloodBonuses(challenge); err != nil {
+		return err
+	}
+	if err := validateScoring(challenge); err != nil {
+		return err
+	}
+	if err := normalizeSlug(challenge); err != nil {
+		return err
+	}
+	if err := validateVisibility(challenge); err != nil {
+		return err
+	}
+	normalizeTags(challenge)
+
+	err = s.challengeRepo.UpdateChallenge(id, challenge)
 	if err == nil {
-		s.invalidateScoreboardCache()
+		invalidateScoreboardCache()
+		// Announce challenges that players can see from now on; scheduled releases are announced by WatchReleases
+		now := time.Now()
+		if challenge.IsReleased(now) && !existing.IsReleased(now) {
+			s.publishReleases([]models.Challenge{*challenge})
+		}
 	}
-	return err
+	return slugError(err)
 }
 
 func (s *ChallengeService) DeleteChallenge(id string) error {
-	err := s.challengeRepo.DeleteChallenge(id)
+	challenge, err := s.challengeRepo.GetChallengeByID(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.challengeRepo.DeleteChallenge(id)
 	if err == nil {
-		s.invalidateScoreboardCache()
+		// Clean up uploaded attachments once the challenge is gone
+		s.fileService.DeleteChallengeFiles(challenge)
+		if err := s.challengeRepo.RemovePrerequisite(challenge.ID); err != nil {
+			log.Printf("Warning: failed to remove challenge %s from prerequisites: %v", id, err)
+		}
+		invalidateScoreboardCache()
 	}
 	return err
 }
 
+// ChallengeAccess describes what a team (or teamless user) has solved, to decide which challenges are unlocked
+type ChallengeAccess struct {
+	solved map[primitive.ObjectID]bool
+	score  int
+}
+
+// IsSolved reports whether the challenge has been solved
+func (a *ChallengeAccess) IsSolved(challengeID primitive.ObjectID) bool {
+	return a.solved[challengeID]
+}
+
+// SolvedIDs returns the IDs of the solved challenges
+func (a *ChallengeAccess) SolvedIDs() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(a.solved))
+	for id := range a.solved {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// IsUnlocked reports whether every prerequisite is solved and the minimum score is reached
+func (a *ChallengeAccess) IsUnlocked(challenge *models.Challenge) bool {
+	if a.score < challenge.MinTeamScore {
+		return false
+	}
+	for _, id := range challenge.Prerequisites {
+		if !a.solved[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// GetChallengeAccess returns the solves and score that decide which challenges the user can open
+func (s *ChallengeService) GetChallengeAccess(userID primitive.ObjectID) (*ChallengeAccess, error) {
+	team, _ := s.teamRepo.FindTeamByMemberID(userID.Hex())
+	return s.accessFor(userID, team)
+}
+
+// EnsureUnlocked returns ErrChallengeLocked if the user's team has not unlocked the challenge.
+// Challenges players cannot see yet are reported as not found.
+func (s *ChallengeService) EnsureUnlocked(userID primitive.ObjectID, challengeID string) error {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil || !challenge.IsReleased(time.Now()) {
+		return errors.New("challenge not found")
+	}
+	if !challenge.HasUnlockRequirements() {
+		return nil
+	}
+
+	access, err := s.GetChallengeAccess(userID)
+	if err != nil {
+		return err
+	}
+	if !access.IsUnlocked(challenge) {
+		return ErrChallengeLocked
+	}
+	return nil
+}
+
+// EnsureOpen returns ErrChallengeArchived if the challenge no longer accepts flags or
+// hint unlocks; challenges players cannot see yet are reported as not found
+func (s *ChallengeService) EnsureOpen(challengeID string) error {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil || !challenge.IsReleased(time.Now()) {
+		return errors.New("challenge not found")
+	}
+	if !challenge.IsOpen(time.Now()) {
+		return ErrChallengeArchived
+	}
+	return nil
+}
+
+// accessFor collects the solves of the team (or teamless user) and their score:
+// the current value of each solved challenge plus blood bonuses, minus hint costs
+func (s *ChallengeService) accessFor(userID primitive.ObjectID, team *models.Team) (*ChallengeAccess, error) {
+	var submissions []models.Submission
+	var err error
+	teamID := primitive.NilObjectID
+	if team != nil {
+		teamID = team.ID
+		submissions, err = s.submissionRepo.GetTeamSubmissions(team.ID)
+	} else {
+		submissions, err = s.submissionRepo.GetUserCorrectSubmissions(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return nil, err
+	}
+	challengePoints := make(map[primitive.ObjectID]int)
+	for _, c := range challenges {
+		challengePoints[c.ID] = c.CurrentPoints()
+	}
+
+	access := &ChallengeAccess{solved: make(map[primitive.ObjectID]bool)}
+	for _, sub := range submissions {
+		points, exists := challengePoints[sub.ChallengeID]
+		if !exists || access.solved[sub.ChallengeID] {
+			continue
+		}
+		access.solved[sub.ChallengeID] = true
+		access.score += points
+	}
+
+	ownerID := userID
+	if team != nil {
+		ownerID = team.ID
+	}
+	solves, err := s.solveRepo.GetOwnerSolves(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, solve := range solves {
+		if _, exists := challengePoints[solve.ChallengeID]; exists {
+			access.score += solve.Bonus
+		}
+	}
+
+	unlocks, err := s.hintUnlockRepo.GetUnlocksFor(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unlocks {
+		if _, exists := challengePoints[u.ChallengeID]; exists {
+			access.score -= u.Cost
+		}
+	}
+
+	var awards []models.Award
+	if team != nil {
+		awards, err = s.awardRepo.GetTeamAwards(team.ID)
+	} else {
+		awards, err = s.awardRepo.GetUserAwards(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, award := range awards {
+		access.score += award.Points
+	}
+
+	return access, nil
+}
+
 // SubmitFlagResult contains the result of a flag submission
 type SubmitFlagResult struct {
 	IsCorrect     bool   `json:"is_correct"`
@@ -76,6 +569,9 @@ type SubmitFlagResult struct {
 	TeamName      string `json:"team_name,omitempty"`
 	Points        int    `json:"points,omitempty"`
 	SolveCount    int    `json:"solve_count,omitempty"`
+	SolvePosition int    `json:"solve_position,omitempty"`
+	BloodBonus    int    `json:"blood_bonus,omitempty"`
+	Badge         string `json:"badge,omitempty"` // first_blood, second_blood or third_blood
 	Message       string `json:"message,omitempty"`
 }
 
@@ -85,6 +581,11 @@ func (s *ChallengeService) SubmitFlag(userID primitive.ObjectID, challengeID str
 		return nil, err
 	}
 
+	// Tokens issued before a ban stay valid until they expire, so check here too
+	if user, err := s.userRepo.FindByID(userID.Hex()); err == nil && user.Banned {
+		return nil, ErrUserBanned
+	}
+
 	cid, _ := primitive.ObjectIDFromHex(challengeID)
 
 	result := &SubmitFlagResult{}
@@ -107,74 +608,41 @@ func (s *ChallengeService) SubmitFlag(userID primitive.ObjectID, challengeID str
 		return result, nil
 	}
 
-	// Verify flag using hash comparison
-	isCorrect := utils.VerifyFlag(flag, challenge.FlagHash)
-	result.IsCorrect = isCorrect
-
 	// Check if user is in a team
 	team, _ := s.teamRepo.FindTeamByMemberID(userID.Hex())
 
-	if team != nil {
-		result.TeamID = team.ID.Hex()
-		result.TeamName = team.Name
-
-		// 2. Check if TEAM already solved it
-		teamAlreadySolved := false
-		existingTeamSolve, _ := s.submissionRepo.FindByChallengeAndTeam(cid, team.ID)
-		if existingTeamSolve != nil {
-			teamAlreadySolved = true
-		}
-
-		// Hash the submitted flag for storage
-		flagHash := utils.HashFlag(flag)
-
-		submission := &models.Submission{
-			UserID:      userID,
-			TeamID:      team.ID,
-			ChallengeID: cid,
-			Flag:        flagHash,
-			IsCorrect:   isCorrect,
-		}
-
-		err = s.submissionRepo.CreateSubmission(submission)
+	if challenge.HasUnlockRequirements() {
+		access, err := s.accessFor(userID, team)
 		if err != nil {
 			return nil, err
 		}
+		if !access.IsUnlocked(challenge) {
+			return nil, ErrChallengeLocked
+		}
+	}
+
+	// Dynamic flags are derived per team, or per user when they have no team
+	ownerID := userID
+	if team != nil {
+		ownerID = team.ID
+	}
 
+	isCorrect := s.verifyFlag(challenge, flag, ownerID)
+	result.IsCorrect = isCorrect
+
+	// Only admins reach challenges players cannot see yet. Their test submissions are
+	// not recorded, so they take no blood, do not decay the points and are not streamed.
+	if !challenge.IsReleased(time.Now()) {
 		if isCorrect {
-			// Invalidate cache since scoreboard will change (at least individual)
-			s.invalidateScoreboardCache()
-
-			// If team hasn't solved it before, increment solve count and award points
-			if !teamAlreadySolved {
-				// Increment global solve count (unique teams/individuals)
-				s.challengeRepo.IncrementSolveCount(challengeID)
-				
-				// Refresh challenge to get updated solve count
-				challenge, _ = s.challengeRepo.GetChallengeByID(challengeID)
-				
-				// Calculate dynamic points
-				points := challenge.CurrentPoints()
-				result.Points = points
-				result.SolveCount = challenge.SolveCount
-				
-				// Award points to team
-				s.teamRepo.UpdateTeamScore(team.ID.Hex(), points)
-				
-				result.Message = "Flag correct! Points awarded to team " + team.Name
-			} else {
-				// Team already solved, but this user just solved it
-				result.AlreadySolved = true // From team perspective
-				result.Points = challenge.CurrentPoints()
-				result.SolveCount = challenge.SolveCount
-				result.Message = "Flag correct! (Team already solved)"
-			}
+			result.Message = "Flag correct! (Challenge not released, no solve recorded)"
 		}
-
 		return result, nil
 	}
 
-	// Individual submission (no team)
+	if !isCorrect && challenge.IsDynamicFlag() {
+		s.detectFlagSharing(challenge, flag, userID, ownerID, team)
+	}
+
 	// Hash the submitted flag for storage
 	flagHash := utils.HashFlag(flag)
 
@@ -184,23 +652,305 @@ func (s *ChallengeService) SubmitFlag(userID primitive.ObjectID, challengeID str
 		Flag:        flagHash,
 		IsCorrect:   isCorrect,
 	}
+	if team != nil {
+		submission.TeamID = team.ID
+		result.TeamID = team.ID.Hex()
+		result.TeamName = team.Name
+	}
 
-	err = s.submissionRepo.CreateSubmission(submission)
+	if !isCorrect {
+		if err := s.submissionRepo.CreateSubmission(submission); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// 2. Record the solve atomically; only the first correct submission of a
+	// team (or teamless user) increments the solve count and awards points
+	challenge, solve, err := s.solveRepo.RecordSolve(submission, ownerID)
 	if err != nil {
 		return nil, err
 	}
 
-	if isCorrect {
-		// Increment solve count
-		s.challengeRepo.IncrementSolveCount(challengeID)
-		
-		// Refresh challenge to get updated solve count
-		challenge, _ = s.challengeRepo.GetChallengeByID(challengeID)
-		
-		result.Points = challenge.CurrentPoints()
-		result.SolveCount = challenge.SolveCount
-		s.invalidateScoreboardCache()
+	// Invalidate cache since scoreboard will change (at least individual)
+	invalidateScoreboardCache()
+
+	result.Points = challenge.CurrentPoints()
+	result.SolveCount = challenge.SolveCount
+	if solve == nil {
+		result.AlreadySolved = true // From team perspective
+	} else {
+		result.SolvePosition = solve.Position
+		result.BloodBonus = solve.Bonus
+		result.Badge = models.BloodBadge(solve.Position)
+		s.publishSolve(challenge, solve, team)
+	}
+	if team != nil {
+		if solve != nil {
+			result.Message = "Flag correct! Points awarded to team " + team.Name
+		} else {
+			result.Message = "Flag correct! (Team already solved)"
+		}
 	}
 
 	return result, nil
-}
\ No newline at end of file
+}
+
+// SolveEvent is streamed to clients when a team (or teamless user) solves a challenge.
+// Title and category are empty for challenges hidden while locked.
+type SolveEvent struct {
+	ChallengeID    string `json:"challenge_id"`
+	ChallengeTitle string `json:"challenge_title"`
+	Category       string `json:"category"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamID         string `json:"team_id,omitempty"`
+	TeamName       string `json:"team_name,omitempty"`
+	Points         int    `json:"points"`
+	BloodBonus     int    `json:"blood_bonus,omitempty"`
+	SolveCount     int    `json:"solve_count"`
+	Badge          string `json:"badge,omitempty"`
+}
+
+// ScoreboardDelta describes how a solve changed the scoreboard: the solver gains
+// Points (including any blood bonus), and the challenge is now worth ChallengePoints
+// to everyone who solved it
+type ScoreboardDelta struct {
+	TeamID          string `json:"team_id,omitempty"`
+	TeamName        string `json:"team_name,omitempty"`
+	UserID          string `json:"user_id"`
+	Username        string `json:"username"`
+	ChallengeID     string `json:"challenge_id"`
+	Points          int    `json:"points"`
+	ChallengePoints int    `json:"challenge_points"`
+}
+
+// publishSolve streams a solve, first blood and scoreboard delta; nothing is
+// published while the scoreboard is frozen so the freeze does not leak
+func (s *ChallengeService) publishSolve(challenge *models.Challenge, solve *models.Solve, team *models.Team) {
+	if !s.eventService.FreezeCutoff().IsZero() {
+		return
+	}
+
+	event := SolveEvent{
+		ChallengeID: challenge.ID.Hex(),
+		UserID:      solve.UserID.Hex(),
+		Username:    "Unknown",
+		Points:      challenge.CurrentPoints(),
+		BloodBonus:  solve.Bonus,
+		SolveCount:  challenge.SolveCount,
+		Badge:       models.BloodBadge(solve.Position),
+	}
+	// The stream is public, so challenges hidden while locked are announced without
+	// the title and category players who have not unlocked them cannot see
+	if !challenge.HiddenWhileLocked() {
+		event.ChallengeTitle = challenge.Title
+		event.Category = challenge.Category
+	}
+	if user, err := s.userRepo.FindByID(solve.UserID.Hex()); err == nil {
+		event.Username = user.Username
+	}
+	if team != nil {
+		event.TeamID = team.ID.Hex()
+		event.TeamName = team.Name
+	}
+
+	if err := s.broker.Publish(realtime.EventSolve, event); err != nil {
+		log.Printf("Warning: failed to publish solve event: %v", err)
+	}
+	if solve.Position == 1 {
+		if err := s.broker.Publish(realtime.EventFirstBlood, event); err != nil {
+			log.Printf("Warning: failed to publish first blood event: %v", err)
+		}
+	}
+
+	delta := ScoreboardDelta{
+		TeamID:          event.TeamID,
+		TeamName:        event.TeamName,
+		UserID:          event.UserID,
+		Username:        event.Username,
+		ChallengeID:     event.ChallengeID,
+		Points:          event.Points + event.BloodBonus,
+		ChallengePoints: event.Points,
+	}
+	if err := s.broker.Publish(realtime.EventScoreboard, delta); err != nil {
+		log.Printf("Warning: failed to publish scoreboard delta: %v", err)
+	}
+}
+
+// verifyFlag checks a submitted flag against each of the challenge's flags in turn, or the owner's dynamic flag
+func (s *ChallengeService) verifyFlag(challenge *models.Challenge, flag string, ownerID primitive.ObjectID) bool {
+	if challenge.IsDynamicFlag() {
+		return utils.VerifyDynamicFlag(flag, challenge.FlagSecret, challenge.FlagFormat, challenge.ID.Hex(), ownerID.Hex())
+	}
+	for _, f := range challenge.StaticFlags() {
+		if f.Matches(flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectFlagSharing records a cheat report when a wrong submission is another team's dynamic flag
+func (s *ChallengeService) detectFlagSharing(challenge *models.Challenge, flag string, userID, ownerID primitive.ObjectID, team *models.Team) {
+	source, isTeam := s.findDynamicFlagOwner(challenge, flag)
+	if source.IsZero() || source == ownerID {
+		return
+	}
+
+	report := &models.CheatReport{
+		ChallengeID:     challenge.ID,
+		SubmitterUserID: userID,
+		SourceOwnerID:   source,
+		SourceIsTeam:    isTeam,
+	}
+	if team != nil {
+		report.SubmitterTeamID = team.ID
+	}
+
+	log.Printf("Flag sharing detected on challenge %s: submitted by user %s, flag belongs to %s", challenge.ID.Hex(), userID.Hex(), source.Hex())
+	if err := s.cheatRepo.CreateReport(report); err != nil {
+		log.Printf("Warning: failed to record cheat report for challenge %s: %v", challenge.ID.Hex(), err)
+	}
+}
+
+// findDynamicFlagOwner returns the team (or teamless user) whose derived flag matches
+func (s *ChallengeService) findDynamicFlagOwner(challenge *models.Challenge, flag string) (primitive.ObjectID, bool) {
+	teams, err := s.teamRepo.GetAllTeamsWithScores()
+	if err == nil {
+		for _, t := range teams {
+			if s.verifyFlag(challenge, flag, t.ID) {
+				return t.ID, true
+			}
+		}
+	}
+
+	users, err := s.userRepo.GetAllUsers()
+	if err == nil {
+		for _, u := range users {
+			if s.verifyFlag(challenge, flag, u.ID) {
+				return u.ID, false
+			}
+		}
+	}
+
+	return primitive.NilObjectID, false
+}
+
+// DynamicFlag is the derived flag of one team (or teamless user), for deploying challenge instances
+type DynamicFlag struct {
+	OwnerID   string `json:"owner_id"`
+	OwnerName string `json:"owner_name"`
+	IsTeam    bool   `json:"is_team"`
+	Flag      string `json:"flag"`
+}
+
+// GetDynamicFlags lists the derived flag of every team and teamless user for a dynamic challenge
+func (s *ChallengeService) GetDynamicFlags(challengeID string) ([]DynamicFlag, error) {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, errors.New("challenge not found")
+	}
+	if !challenge.IsDynamicFlag() {
+		return nil, errors.New("challenge does not use dynamic flags")
+	}
+
+	teams, err := s.teamRepo.GetAllTeamsWithScores()
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	inTeam := make(map[primitive.ObjectID]bool)
+	flags := make([]DynamicFlag, 0, len(teams)+len(users))
+	for _, t := range teams {
+		for _, mid := range t.MemberIDs {
+			inTeam[mid] = true
+		}
+		flags = append(flags, DynamicFlag{
+			OwnerID:   t.ID.Hex(),
+			OwnerName: t.Name,
+			IsTeam:    true,
+			Flag:      utils.DeriveDynamicFlag(challenge.FlagSecret, challenge.FlagFormat, challenge.ID.Hex(), t.ID.Hex()),
+		})
+	}
+	for _, u := range users {
+		if inTeam[u.ID] {
+			continue
+		}
+		flags = append(flags, DynamicFlag{
+			OwnerID:   u.ID.Hex(),
+			OwnerName: u.Username,
+			Flag:      utils.DeriveDynamicFlag(challenge.FlagSecret, challenge.FlagFormat, challenge.ID.Hex(), u.ID.Hex()),
+		})
+	}
+
+	return flags, nil
+}
+
+// CheatReportInfo is a cheat report with names resolved for the admin view
+type CheatReportInfo struct {
+	ID                string `json:"id"`
+	ChallengeID       string `json:"challenge_id"`
+	ChallengeTitle    string `json:"challenge_title"`
+	SubmitterUserID   string `json:"submitter_user_id"`
+	SubmitterUsername string `json:"submitter_username"`
+	SubmitterTeamID   string `json:"submitter_team_id,omitempty"`
+	SubmitterTeamName string `json:"submitter_team_name,omitempty"`
+	SourceOwnerID     string `json:"source_owner_id"`
+	SourceOwnerName   string `json:"source_owner_name"`
+	SourceIsTeam      bool   `json:"source_is_team"`
+	CreatedAt         string `json:"created_at"`
+}
+
+// GetCheatReports returns all flag sharing reports, newest first
+func (s *ChallengeService) GetCheatReports() ([]CheatReportInfo, error) {
+	reports, err := s.cheatRepo.GetAllReports()
+	if err != nil {
+		return nil, err
+	}
+
+	teamName := func(id primitive.ObjectID) string {
+		if team, err := s.teamRepo.FindTeamByID(id.Hex()); err == nil {
+			return team.Name
+		}
+		return "Unknown"
+	}
+	username := func(id primitive.ObjectID) string {
+		if user, err := s.userRepo.FindByID(id.Hex()); err == nil {
+			return user.Username
+		}
+		return "Unknown"
+	}
+
+	result := make([]CheatReportInfo, 0, len(reports))
+	for _, r := range reports {
+		info := CheatReportInfo{
+			ID:                r.ID.Hex(),
+			ChallengeID:       r.ChallengeID.Hex(),
+			SubmitterUserID:   r.SubmitterUserID.Hex(),
+			SubmitterUsername: username(r.SubmitterUserID),
+			SourceOwnerID:     r.SourceOwnerID.Hex(),
+			SourceIsTeam:      r.SourceIsTeam,
+			CreatedAt:         r.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if challenge, err := s.challengeRepo.GetChallengeByID(r.ChallengeID.Hex()); err == nil {
+			info.ChallengeTitle = challenge.Title
+		}
+		if !r.SubmitterTeamID.IsZero() {
+			info.SubmitterTeamID = r.SubmitterTeamID.Hex()
+			info.SubmitterTeamName = teamName(r.SubmitterTeamID)
+		}
+		if r.SourceIsTeam {
+			info.SourceOwnerName = teamName(r.SourceOwnerID)
+		} else {
+			info.SourceOwnerName = username(r.SourceOwnerID)
+		}
+		result = append(result, info)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,256 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/realtime"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/go-ctf-platform/backend/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestChallengeService(t *testing.T) *ChallengeService {
+	t.Helper()
+
+	solveRepo := repositories.NewSolveRepository()
+	if err := solveRepo.EnsureIndexes(); err != nil {
+		t.Fatal(err)
+	}
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challengeRepo := repositories.NewChallengeRepository()
+	return NewChallengeService(
+		challengeRepo,
+		repositories.NewSubmissionRepository(),
+		repositories.NewTeamRepository(),
+		NewFileService(challengeRepo, store, 1),
+		repositories.NewUserRepository(),
+		repositories.NewCheatReportRepository(),
+		repositories.NewHintUnlockRepository(),
+		solveRepo,
+		repositories.NewAwardRepository(),
+		NewEventService(repositories.NewEventRepository()),
+		realtime.NewMemoryBroker(),
+	)
+}
+
+func createTestChallenge(t *testing.T, s *ChallengeService, flag string) *models.Challenge {
+	t.Helper()
+
+	f, err := models.NewFlag(models.FlagTypeStatic, flag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge := &models.Challenge{
+		Title:      "Race",
+		Category:   "misc",
+		Difficulty: "easy",
+		MaxPoints:  500,
+		MinPoints:  500,
+		Scoring:    models.ScoringStatic,
+		Flags:      []models.Flag{f},
+	}
+	if err := s.CreateChallenge(challenge); err != nil {
+		t.Fatal(err)
+	}
+	return challenge
+}
+
+func createTestUsers(t *testing.T, n int) []primitive.ObjectID {
+	t.Helper()
+
+	userRepo := repositories.NewUserRepository()
+	ids := make([]primitive.ObjectID, n)
+	for i := range ids {
+		user := &models.User{
+			ID:            primitive.NewObjectID(),
+			Username:      "player" + primitive.NewObjectID().Hex(),
+			Email:         primitive.NewObjectID().Hex() + "@example.com",
+			Role:          "user",
+			EmailVerified: true,
+		}
+		if err := userRepo.CreateUser(user); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+// submitConcurrently submits the flag once per user ID, all at the same time
+func submitConcurrently(t *testing.T, s *ChallengeService, challengeID string, userIDs []primitive.ObjectID, flag string) []*SubmitFlagResult {
+	t.Helper()
+
+	results := make([]*SubmitFlagResult, len(userIDs))
+	errs := make([]error, len(userIDs))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func(i int, userID primitive.ObjectID) {
+			defer wg.Done()
+			<-start
+			results[i], errs[i] = s.SubmitFlag(userID, challengeID, flag)
+		}(i, userID)
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("SubmitFlag: %v", err)
+		}
+	}
+	return results
+}
+
+// assertSingleSolve checks the challenge was solved exactly once by ownerID
+func assertSingleSolve(t *testing.T, challengeID, ownerID primitive.ObjectID, results []*SubmitFlagResult) {
+	t.Helper()
+
+	firstSolves := 0
+	for _, r := range results {
+		if !r.IsCorrect {
+			t.Fatalf("submission was not accepted: %+v", r)
+		}
+		if !r.AlreadySolved {
+			firstSolves++
+		}
+	}
+	if firstSolves != 1 {
+		t.Errorf("%d submissions counted as the solve, want 1", firstSolves)
+	}
+
+	ctx := context.Background()
+	solves, err := database.DB.Collection("solves").CountDocuments(ctx, bson.M{"challenge_id": challengeID, "owner_id": ownerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solves != 1 {
+		t.Errorf("%d solves recorded, want 1", solves)
+	}
+
+	challenge, err := repositories.NewChallengeRepository().GetChallengeByID(challengeID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.SolveCount != 1 {
+		t.Errorf("solve_count = %d, want 1", challenge.SolveCount)
+	}
+}
+
+func TestSubmitFlagConcurrentTeamSolvesOnce(t *testing.T) {
+	setupTestDB(t)
+	s := newTestChallengeService(t)
+	challenge := createTestChallenge(t, s, "flag{race}")
+
+	members := createTestUsers(t, 8)
+	team := &models.Team{Name: "racers", LeaderID: members[0], MemberIDs: members}
+	if err := repositories.NewTeamRepository().CreateTeam(team); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every member submits twice at the same time
+	submitters := append(append([]primitive.ObjectID{}, members...), members...)
+	results := submitConcurrently(t, s, challenge.ID.Hex(), submitters, "flag{race}")
+	assertSingleSolve(t, challenge.ID, team.ID, results)
+
+	team, err := repositories.NewTeamRepository().FindTeamByID(team.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Score != 500 {
+		t.Errorf("team score = %d, want 500", team.Score)
+	}
+}
+
+func TestSubmitFlagConcurrentUserSolvesOnce(t *testing.T) {
+	setupTestDB(t)
+	s := newTestChallengeService(t)
+	challenge := createTestChallenge(t, s, "flag{race}")
+
+	user := createTestUsers(t, 1)[0]
+	submitters := make([]primitive.ObjectID, 16)
+	for i := range submitters {
+		submitters[i] = user
+	}
+	results := submitConcurrently(t, s, challenge.ID.Hex(), submitters, "flag{race}")
+	assertSingleSolve(t, challenge.ID, user, results)
+
+	// The user scoreboard credits the solve once too
+	scoreboard := NewScoreboardService(
+		repositories.NewUserRepository(),
+		repositories.NewSubmissionRepository(),
+		repositories.NewChallengeRepository(),
+		repositories.NewTeamRepository(),
+		repositories.NewHintUnlockRepository(),
+		repositories.NewSolveRepository(),
+		repositories.NewAwardRepository(),
+		NewEventService(repositories.NewEventRepository()),
+	)
+	scores, err := scoreboard.GetLiveScoreboard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scores) != 1 || scores[0].Score != 500 {
+		t.Errorf("scoreboard = %+v, want one user with 500 points", scores)
+	}
+}
+
+func TestSubmitFlagUnreleasedChallengeRecordsNothing(t *testing.T) {
+	setupTestDB(t)
+	s := newTestChallengeService(t)
+	challenge := createTestChallenge(t, s, "flag{draft}")
+	challenge.Visibility = models.VisibilityDraft
+	if err := repositories.NewChallengeRepository().UpdateChallenge(challenge.ID.Hex(), challenge); err != nil {
+		t.Fatal(err)
+	}
+
+	events, unsubscribe := s.broker.Subscribe()
+	defer unsubscribe()
+
+	// An admin testing the draft's flag
+	user := createTestUsers(t, 1)[0]
+	result, err := s.SubmitFlag(user, challenge.ID.Hex(), "flag{draft}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsCorrect || result.SolvePosition != 0 || result.Badge != "" {
+		t.Errorf("result = %+v, want a correct flag without a solve", result)
+	}
+
+	ctx := context.Background()
+	for _, collection := range []string{"solves", "submissions"} {
+		n, err := database.DB.Collection(collection).CountDocuments(ctx, bson.M{"challenge_id": challenge.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 {
+			t.Errorf("%d %s recorded, want 0", n, collection)
+		}
+	}
+
+	challenge, err = repositories.NewChallengeRepository().GetChallengeByID(challenge.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.SolveCount != 0 {
+		t.Errorf("solve_count = %d, want 0", challenge.SolveCount)
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("published %s for an unreleased challenge", event.Type)
+	default:
+	}
+}
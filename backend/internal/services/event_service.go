@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventService struct {
+	eventRepo *repositories.EventRepository
+}
+
+func NewEventService(eventRepo *repositories.EventRepository) *EventService {
+	return &EventService{
+		eventRepo: eventRepo,
+	}
+}
+
+// GetConfig returns the current event configuration
+func (s *EventService) GetConfig() (*models.EventConfig, error) {
+	return s.eventRepo.GetConfig()
+}
+
+// UpdateConfig validates and stores a new event configuration
+func (s *EventService) UpdateConfig(cfg *models.EventConfig, adminID primitive.ObjectID) error {
+	if !cfg.StartTime.IsZero() && !cfg.EndTime.IsZero() && !cfg.EndTime.After(cfg.StartTime) {
+		return errors.New("end time must be after start time")
+	}
+	if !cfg.FreezeTime.IsZero() {
+		if !cfg.StartTime.IsZero() && cfg.FreezeTime.Before(cfg.StartTime) {
+			return errors.New("freeze time must not be before start time")
+		}
+		if !cfg.EndTime.IsZero() && cfg.FreezeTime.After(cfg.EndTime) {
+			return errors.New("freeze time must not be after end time")
+		}
+	}
+
+	cfg.UpdatedBy = adminID
+	if err := s.eventRepo.SaveConfig(cfg); err != nil {
+		return err
+	}
+
+	// The freeze time decides which scoreboard the public sees
+	invalidateScoreboardCache()
+	return nil
+}
+
+// CheckChallengeAccess returns an error if players may not view challenges yet
+func (s *EventService) CheckChallengeAccess() error {
+	cfg, err := s.eventRepo.GetConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.Status(time.Now()) == models.EventStatusNotStarted {
+		return errors.New("the competition has not started yet")
+	}
+	return nil
+}
+
+// CheckSubmissionAllowed returns an error if flags cannot be submitted right now
+func (s *EventService) CheckSubmissionAllowed() error {
+	cfg, err := s.eventRepo.GetConfig()
+	if err != nil {
+		return err
+	}
+	switch cfg.Status(time.Now()) {
+	case models.EventStatusNotStarted:
+		return errors.New("the competition has not started yet")
+	case models.EventStatusEnded:
+		return errors.New("the competition has ended")
+	case models.EventStatusPaused:
+		return errors.New("the competition is paused")
+	}
+	return nil
+}
+
+// FreezeCutoff returns the freeze time if the public scoreboard is currently frozen, or a zero time
+func (s *EventService) FreezeCutoff() time.Time {
+	cfg, err := s.eventRepo.GetConfig()
+	if err != nil || !cfg.IsFrozen(time.Now()) {
+		return time.Time{}
+	}
+	return cfg.FreezeTime
+}
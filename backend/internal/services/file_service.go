@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/go-ctf-platform/backend/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrFileChallengeNotFound is returned for attachments of an unknown challenge
+	ErrFileChallengeNotFound = errors.New("challenge not found")
+	// ErrFileNotFound is returned for an attachment the challenge does not have
+	ErrFileNotFound = errors.New("file not found")
+	// ErrInvalidFileID is returned for a malformed attachment ID
+	ErrInvalidFileID = errors.New("invalid file ID")
+	// ErrFileTooLarge is returned for uploads over the configured maximum size
+	ErrFileTooLarge = errors.New("file exceeds maximum upload size")
+)
+
+type FileService struct {
+	challengeRepo *repositories.ChallengeRepository
+	storage       storage.Storage
+	maxSize       int64
+}
+
+func NewFileService(challengeRepo *repositories.ChallengeRepository, store storage.Storage, maxSizeMB int64) *FileService {
+	return &FileService{
+		challengeRepo: challengeRepo,
+		storage:       store,
+		maxSize:       maxSizeMB * 1024 * 1024,
+	}
+}
+
+// MaxSize returns the maximum upload size in bytes
+func (s *FileService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// sanitizeFileName strips any directory components from an uploaded file name
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	return name
+}
+
+// UploadFile stores an attachment for a challenge and records its SHA-256 checksum
+func (s *FileService) UploadFile(challengeID, name, contentType string, r io.Reader, size int64) (*models.ChallengeFile, error) {
+	if size > s.maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	if _, err := s.challengeRepo.GetChallengeByID(challengeID); err != nil {
+		return nil, ErrFileChallengeNotFound
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	fileID := primitive.NewObjectID()
+	file := &models.ChallengeFile{
+		ID:          fileID,
+		Name:        sanitizeFileName(name),
+		Key:         "challenges/" + challengeID + "/" + fileID.Hex(),
+		Size:        size,
+		ContentType: contentType,
+		UploadedAt:  time.Now(),
+	}
+
+	// Hash while streaming to the backend so the file is read only once
+	hasher := sha256.New()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.storage.Put(ctx, file.Key, io.TeeReader(io.LimitReader(r, size), hasher), size, contentType); err != nil {
+		return nil, err
+	}
+	file.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+
+	if err := s.challengeRepo.AddAttachment(challengeID, file); err != nil {
+		s.storage.Delete(context.Background(), file.Key)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// OpenFile returns the attachment metadata and a reader for its content; the caller must close it
+func (s *FileService) OpenFile(challengeID, fileID string) (*models.ChallengeFile, io.ReadCloser, error) {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, nil, ErrFileChallengeNotFound
+	}
+
+	fid, err := primitive.ObjectIDFromHex(fileID)
+	if err != nil {
+		return nil, nil, ErrInvalidFileID
+	}
+
+	file := challenge.FindAttachment(fid)
+	if file == nil {
+		return nil, nil, ErrFileNotFound
+	}
+
+	reader, err := s.storage.Get(context.Background(), file.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, reader, nil
+}
+
+// DeleteFile removes an attachment from a challenge and from storage
+func (s *FileService) DeleteFile(challengeID, fileID string) error {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return ErrFileChallengeNotFound
+	}
+
+	fid, err := primitive.ObjectIDFromHex(fileID)
+	if err != nil {
+		return ErrInvalidFileID
+	}
+
+	file := challenge.FindAttachment(fid)
+	if file == nil {
+		return ErrFileNotFound
+	}
+
+	if err := s.challengeRepo.RemoveAttachment(challengeID, fid); err != nil {
+		return err
+	}
+
+	return s.storage.Delete(context.Background(), file.Key)
+}
+
+// DeleteChallengeFiles removes every stored attachment of a challenge
+func (s *FileService) DeleteChallengeFiles(challenge *models.Challenge) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for _, file := range challenge.Attachments {
+		if err := s.storage.Delete(ctx, file.Key); err != nil {
+			log.Printf("Warning: failed to delete stored file %s: %v", file.Key, err)
+		}
+	}
+}
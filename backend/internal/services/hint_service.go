@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type HintService struct {
+	challengeRepo  *repositories.ChallengeRepository
+	hintUnlockRepo *repositories.HintUnlockRepository
+	teamRepo       *repositories.TeamRepository
+	userRepo       *repositories.UserRepository
+}
+
+func NewHintService(
+	challengeRepo *repositories.ChallengeRepository,
+	hintUnlockRepo *repositories.HintUnlockRepository,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+) *HintService {
+	return &HintService{
+		challengeRepo:  challengeRepo,
+		hintUnlockRepo: hintUnlockRepo,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// HintView is a hint as seen by a player; content is only set once unlocked
+type HintView struct {
+	ID       string `json:"id"`
+	Order    int    `json:"order"`
+	Cost     int    `json:"cost"`
+	Unlocked bool   `json:"unlocked"`
+	Content  string `json:"content,omitempty"`
+}
+
+// sortedHints returns the challenge hints ordered by their Order field
+func sortedHints(challenge *models.Challenge) []models.Hint {
+	hints := make([]models.Hint, len(challenge.Hints))
+	copy(hints, challenge.Hints)
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Order < hints[j].Order
+	})
+	return hints
+}
+
+// teamIDFor returns the user's team ID, or a zero ID if they are not in a team
+func (s *HintService) teamIDFor(userID primitive.ObjectID) primitive.ObjectID {
+	team, _ := s.teamRepo.FindTeamByMemberID(userID.Hex())
+	if team == nil {
+		return primitive.NilObjectID
+	}
+	return team.ID
+}
+
+// GetHints returns the hints of a challenge, revealing the ones the user's team has unlocked
+func (s *HintService) GetHints(userID primitive.ObjectID, challengeID string) ([]HintView, error) {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, errors.New("challenge not found")
+	}
+
+	teamID := s.teamIDFor(userID)
+	unlocks, err := s.hintUnlockRepo.GetChallengeUnlocksFor(challenge.ID, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked := make(map[primitive.ObjectID]bool)
+	for _, u := range unlocks {
+		unlocked[u.HintID] = true
+	}
+
+	views := make([]HintView, 0, len(challenge.Hints))
+	for _, hint := range sortedHints(challenge) {
+		view := HintView{
+			ID:       hint.ID.Hex(),
+			Order:    hint.Order,
+			Cost:     hint.Cost,
+			Unlocked: unlocked[hint.ID],
+		}
+		if view.Unlocked {
+			view.Content = hint.Content
+		}
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+// UnlockHint unlocks a hint for the user's team and records the point deduction.
+// Hints must be unlocked in order; unlocking an already unlocked hint is a no-op.
+func (s *HintService) UnlockHint(userID primitive.ObjectID, challengeID, hintID string) (*HintView, error) {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, errors.New("challenge not found")
+	}
+
+	hid, err := primitive.ObjectIDFromHex(hintID)
+	if err != nil {
+		return nil, errors.New("invalid hint ID")
+	}
+
+	hint := challenge.FindHint(hid)
+	if hint == nil {
+		return nil, errors.New("hint not found")
+	}
+
+	teamID := s.teamIDFor(userID)
+	view := &HintView{
+		ID:       hint.ID.Hex(),
+		Order:    hint.Order,
+		Cost:     hint.Cost,
+		Unlocked: true,
+		Content:  hint.Content,
+	}
+
+	existing, _ := s.hintUnlockRepo.FindUnlock(hid, userID, teamID)
+	if existing != nil {
+		return view, nil
+	}
+
+	// Every hint with a lower order must already be unlocked
+	unlocks, err := s.hintUnlockRepo.GetChallengeUnlocksFor(challenge.ID, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := make(map[primitive.ObjectID]bool)
+	for _, u := range unlocks {
+		unlocked[u.HintID] = true
+	}
+	for _, h := range challenge.Hints {
+		if h.Order < hint.Order && !unlocked[h.ID] {
+			return nil, errors.New("previous hints must be unlocked first")
+		}
+	}
+
+	unlock := &models.HintUnlock{
+		HintID:      hid,
+		ChallengeID: challenge.ID,
+		UserID:      userID,
+		TeamID:      teamID,
+		Cost:        hint.Cost,
+	}
+	if err := s.hintUnlockRepo.CreateUnlock(unlock); err != nil {
+		// A concurrent request unlocked it first; it was only charged once
+		if mongo.IsDuplicateKeyError(err) {
+			return view, nil
+		}
+		return nil, err
+	}
+
+	if hint.Cost != 0 {
+		invalidateScoreboardCache()
+	}
+
+	return view, nil
+}
+
+// HintUnlockInfo describes who unlocked a hint (admin view)
+type HintUnlockInfo struct {
+	HintID     string `json:"hint_id"`
+	HintOrder  int    `json:"hint_order"`
+	Cost       int    `json:"cost"`
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	TeamID     string `json:"team_id,omitempty"`
+	TeamName   string `json:"team_name,omitempty"`
+	UnlockedAt string `json:"unlocked_at"`
+}
+
+// GetHintUnlocks returns every unlock of the challenge's hints, newest first
+func (s *HintService) GetHintUnlocks(challengeID string) ([]HintUnlockInfo, error) {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, errors.New("challenge not found")
+	}
+
+	unlocks, err := s.hintUnlockRepo.GetUnlocksByChallenge(challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make(map[primitive.ObjectID]string)
+	teamNames := make(map[primitive.ObjectID]string)
+
+	result := make([]HintUnlockInfo, 0, len(unlocks))
+	for _, u := range unlocks {
+		info := HintUnlockInfo{
+			HintID:     u.HintID.Hex(),
+			Cost:       u.Cost,
+			UserID:     u.UserID.Hex(),
+			UnlockedAt: u.UnlockedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if hint := challenge.FindHint(u.HintID); hint != nil {
+			info.HintOrder = hint.Order
+		}
+
+		if _, ok := usernames[u.UserID]; !ok {
+			usernames[u.UserID] = "Unknown"
+			if user, err := s.userRepo.FindByID(u.UserID.Hex()); err == nil {
+				usernames[u.UserID] = user.Username
+			}
+		}
+		info.Username = usernames[u.UserID]
+
+		if !u.TeamID.IsZero() {
+			if _, ok := teamNames[u.TeamID]; !ok {
+				teamNames[u.TeamID] = ""
+				if team, err := s.teamRepo.FindTeamByID(u.TeamID.Hex()); err == nil {
+					teamNames[u.TeamID] = team.Name
+				}
+			}
+			info.TeamID = u.TeamID.Hex()
+			info.TeamName = teamNames[u.TeamID]
+		}
+
+		result = append(result, info)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,39 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Integration tests run against a real MongoDB, e.g.
+//
+//	MONGO_TEST_URI=mongodb://localhost:27017 go test -tags integration -race ./internal/services/
+//
+// Each test gets its own database, which is dropped when the test ends. Against a
+// replica set the transactional code paths run too.
+func setupTestDB(t *testing.T) {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+	if err := database.Connect(uri, "ctf_test_"+primitive.NewObjectID().Hex()); err != nil {
+		t.Fatal(err)
+	}
+
+	db := database.DB
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if err := db.Drop(ctx); err != nil {
+			t.Errorf("failed to drop test database: %v", err)
+		}
+		db.Client().Disconnect(ctx)
+	})
+}
@@ -2,19 +2,23 @@ package services
 
 import (
 	"errors"
+	"log"
 
 	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/realtime"
 	"github.com/go-ctf-platform/backend/internal/repositories"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type NotificationService struct {
 	notificationRepo *repositories.NotificationRepository
+	broker           realtime.Broker
 }
 
-func NewNotificationService(notificationRepo *repositories.NotificationRepository) *NotificationService {
+func NewNotificationService(notificationRepo *repositories.NotificationRepository, broker realtime.Broker) *NotificationService {
 	return &NotificationService{
 		notificationRepo: notificationRepo,
+		broker:           broker,
 	}
 }
 
@@ -38,6 +42,11 @@ func (s *NotificationService) CreateNotification(title, content, notifType strin
 		return nil, err
 	}
 
+	// Push the new notification to connected clients
+	if err := s.broker.Publish(realtime.EventNotification, notification); err != nil {
+		log.Printf("Warning: failed to publish notification: %v", err)
+	}
+
 	return notification, nil
 }
 
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/oauth"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/oauth2"
+)
+
+// OAuthStateTTL is how long a user has to finish logging in at the provider
+const OAuthStateTTL = 10 * time.Minute
+
+var (
+	// ErrOAuthProviderNotFound is returned for a provider that is not configured
+	ErrOAuthProviderNotFound = errors.New("login provider not found")
+	// ErrInvalidOAuthState is returned when the callback does not match the login the browser started
+	ErrInvalidOAuthState = errors.New("login expired or was started elsewhere, please try again")
+	// ErrOAuthFailed hides provider errors, which may echo provider responses, from users
+	ErrOAuthFailed = errors.New("login with the provider failed, please try again")
+)
+
+// OAuthProviderInfo describes a configured login provider to the frontend
+type OAuthProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	LoginURL    string `json:"login_url"`
+}
+
+// OAuthService logs users in with external OAuth2/OIDC providers. Provider accounts
+// are linked to users through User.OAuth; a first login either links an existing
+// user with the same verified email or creates a new account.
+type OAuthService struct {
+	providers   []oauth.Provider
+	userRepo    *repositories.UserRepository
+	authService *AuthService
+	config      *config.Config
+}
+
+func NewOAuthService(
+	providers []oauth.Provider,
+	userRepo *repositories.UserRepository,
+	authService *AuthService,
+	cfg *config.Config,
+) *OAuthService {
+	return &OAuthService{
+		providers:   providers,
+		userRepo:    userRepo,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+// GetProviders lists the configured providers
+func (s *OAuthService) GetProviders() []OAuthProviderInfo {
+	base := strings.TrimRight(s.config.OAuthCallbackBaseURL, "/")
+	providers := make([]OAuthProviderInfo, 0, len(s.providers))
+	for _, p := range s.providers {
+		providers = append(providers, OAuthProviderInfo{
+			Name:        p.Name(),
+			DisplayName: p.DisplayName(),
+			LoginURL:    base + "/auth/oauth/" + p.Name() + "/login",
+		})
+	}
+	return providers
+}
+
+func (s *OAuthService) provider(name string) (oauth.Provider, error) {
+	for _, p := range s.providers {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, ErrOAuthProviderNotFound
+}
+
+// BeginLogin starts a login at the provider. It returns the URL to send the user to
+// and a signed state token holding the PKCE verifier and nonce, which the browser
+// keeps in a cookie until the provider redirects back.
+func (s *OAuthService) BeginLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state := oauth2.GenerateVerifier()
+	verifier := oauth2.GenerateVerifier()
+	nonce := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, state, verifier, nonce)
+	if err != nil {
+		log.Printf("oauth login with %s failed: %v", provider.Name(), err)
+		return "", "", ErrOAuthFailed
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose":  "oauth",
+		"provider": provider.Name(),
+		"state":    state,
+		"verifier": verifier,
+		"nonce":    nonce,
+		"exp":      time.Now().Add(OAuthStateTTL).Unix(),
+	})
+	stateToken, err := token.SignedString(s.stateKey())
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, stateToken, nil
+}
+
+// CompleteLogin handles the provider's redirect: it checks the state against the
+// browser's state token, redeems the code and logs the linked user in
+func (s *OAuthService) CompleteLogin(ctx context.Context, providerName, code, state, stateToken string, client ClientInfo) (*LoginResult, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.parseStateToken(stateToken)
+	if err != nil || claims["provider"] != provider.Name() {
+		return nil, ErrInvalidOAuthState
+	}
+	expected, _ := claims["state"].(string)
+	verifier, _ := claims["verifier"].(string)
+	nonce, _ := claims["nonce"].(string)
+	if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(state)) != 1 {
+		return nil, ErrInvalidOAuthState
+	}
+
+	identity, err := provider.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		log.Printf("oauth login with %s failed: %v", provider.Name(), err)
+		return nil, ErrOAuthFailed
+	}
+	if identity.Subject == "" {
+		log.Printf("oauth login with %s failed: no subject", provider.Name())
+		return nil, ErrOAuthFailed
+	}
+
+	user, err := s.resolveUser(identity)
+	if err != nil {
+		return nil, err
+	}
+	return s.authService.startLogin(user, client)
+}
+
+// stateKey signs OAuth state tokens. It is derived from the session key like the
+// two-factor challenge key, so neither token can pass as the other.
+func (s *OAuthService) stateKey() []byte {
+	key := sha256.Sum256([]byte("oauth-state:" + s.config.JWTSecret))
+	return key[:]
+}
+
+func (s *OAuthService) parseStateToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.stateKey(), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidOAuthState
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != "oauth" {
+		return nil, ErrInvalidOAuthState
+	}
+	return claims, nil
+}
+
+// resolveUser finds the user linked to the provider account. On the first login
+// it links the user whose verified email matches, or creates a new account.
+func (s *OAuthService) resolveUser(identity *oauth.Identity) (*models.User, error) {
+	user, err := s.userRepo.FindByOAuth(identity.Provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	// Accounts are only matched on an address both sides have verified, otherwise
+	// anyone could claim an account by registering its email at a provider
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, errors.New("your provider account has no verified email address")
+	}
+
+	user, err = s.userRepo.FindByEmail(identity.Email)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if user != nil {
+		return s.linkUser(user, identity)
+	}
+	return s.createUser(identity)
+}
+
+// linkUser attaches the provider account to an existing user
+func (s *OAuthService) linkUser(user *models.User, identity *oauth.Identity) (*models.User, error) {
+	if !user.EmailVerified {
+		return nil, errors.New("an account with this email exists but is not verified - verify it before logging in with a provider")
+	}
+	if user.OAuth != nil {
+		return nil, fmt.Errorf("this account is already linked to %s", user.OAuth.Provider)
+	}
+
+	user.OAuth = &models.OAuth{
+		Provider:   identity.Provider,
+		ProviderID: identity.Subject,
+	}
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser registers a new account for the provider account. It has no password;
+// the user can set one with the password reset flow.
+func (s *OAuthService) createUser(identity *oauth.Identity) (*models.User, error) {
+	username, err := s.uniqueUsername(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		ID:            primitive.NewObjectID(),
+		Username:      username,
+		Email:         identity.Email,
+		Role:          "user",
+		EmailVerified: true,
+		OAuth: &models.OAuth{
+			Provider:   identity.Provider,
+			ProviderID: identity.Subject,
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.userRepo.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// uniqueUsername derives a free username from the provider's suggestion or the email
+func (s *OAuthService) uniqueUsername(identity *oauth.Identity) (string, error) {
+	base := sanitizeUsername(identity.Username)
+	if len(base) < 3 {
+		base = sanitizeUsername(strings.SplitN(identity.Email, "@", 2)[0])
+	}
+	if len(base) < 3 {
+		base = "player"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	username := base
+	for i := 2; i <= 100; i++ {
+		_, err := s.userRepo.FindByUsername(username)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return username, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		username = fmt.Sprintf("%s%d", base, i)
+	}
+	return "", errors.New("could not find a free username")
+}
+
+// sanitizeUsername keeps the letters, digits, dashes and underscores of a name
+func sanitizeUsername(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		case r == ' ' || r == '.':
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(b.String(), "_-")
+}
@@ -0,0 +1,232 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-ctf-platform/backend/internal/config"
+	"github.com/go-ctf-platform/backend/internal/middleware"
+	"github.com/go-ctf-platform/backend/internal/oauth"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/go-jose/go-jose/v4"
+)
+
+const (
+	testOIDCClientID    = "ctf"
+	testOIDCRedirectURL = "http://ctf.test/auth/oauth/oidc/callback"
+)
+
+// testOIDCIssuer is a minimal OpenID Connect issuer that logs every visitor in as
+// the same account. It checks the PKCE verifier when the code is redeemed.
+type testOIDCIssuer struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]interface{}
+
+	mu    sync.Mutex
+	codes map[string]url.Values // Authorization request of each issued code
+}
+
+func newTestOIDCIssuer(t *testing.T, claims map[string]interface{}) *testOIDCIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &testOIDCIssuer{key: key, claims: claims, codes: map[string]url.Values{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                issuer.URL,
+			"authorization_endpoint":                issuer.URL + "/authorize",
+			"token_endpoint":                        issuer.URL + "/token",
+			"jwks_uri":                              issuer.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/authorize", issuer.authorize)
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		issuer.token(t, w, r)
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// authorize logs the user in straight away and redirects back with a code
+func (i *testOIDCIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	code := base64.RawURLEncoding.EncodeToString([]byte(query.Get("state")))
+
+	i.mu.Lock()
+	i.codes[code] = query
+	i.mu.Unlock()
+
+	redirect := query.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+// token redeems a code for an ID token carrying the login's nonce
+func (i *testOIDCIssuer) token(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	i.mu.Lock()
+	request, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || request.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(challenge[:]) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss":   i.URL,
+		"aud":   request.Get("client_id"),
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": request.Get("nonce"),
+	}
+	for k, v := range i.claims {
+		claims[k] = v
+	}
+	payload, _ := json.Marshal(claims)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: i.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	idToken, _ := signed.CompactSerialize()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+// loginWithProvider runs a login through the provider, following the browser's
+// redirects, and returns the result of the callback
+func loginWithProvider(t *testing.T, s *OAuthService) *LoginResult {
+	t.Helper()
+
+	ctx := context.Background()
+	authURL, stateToken, err := s.BeginLogin(ctx, "oidc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	browser := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := browser.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.CompleteLogin(ctx, "oidc", callback.Query().Get("code"), callback.Query().Get("state"), stateToken, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestOAuthFirstLoginCreatesAccount(t *testing.T) {
+	setupTestDB(t)
+	issuer := newTestOIDCIssuer(t, map[string]interface{}{
+		"sub":                "provider-user-1",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"preferred_username": "alice",
+	})
+
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	userRepo := repositories.NewUserRepository()
+	sessionService := NewSessionService(repositories.NewSessionRepository(), repositories.NewRefreshTokenRepository())
+	twoFactorService := NewTwoFactorService(userRepo, repositories.NewSettingsRepository(), cfg)
+	authService := NewAuthService(userRepo, NewEmailService(cfg), twoFactorService, sessionService, cfg)
+	provider := oauth.NewOIDCProvider("oidc", "Test", issuer.URL, testOIDCClientID, "secret", testOIDCRedirectURL)
+	s := NewOAuthService([]oauth.Provider{provider}, userRepo, authService, cfg)
+
+	result := loginWithProvider(t, s)
+	if result.TwoFactorRequired || result.Token == "" {
+		t.Fatalf("login did not start a session: %+v", result)
+	}
+
+	user, err := userRepo.FindByOAuth("oidc", "provider-user-1")
+	if err != nil {
+		t.Fatalf("account was not created: %v", err)
+	}
+	if user.ID.IsZero() || user.Username != "alice" || !user.EmailVerified {
+		t.Errorf("created account = %+v", user)
+	}
+
+	// The session works on an authenticated route that loads the user
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", middleware.AuthMiddleware(cfg, sessionService, nil), func(c *gin.Context) {
+		user, err := userRepo.FindByID(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": user.ID.Hex(), "username": user.Username})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+result.Token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /me = %d %s", rec.Code, rec.Body)
+	}
+	var me struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &me)
+	if me.ID != user.ID.Hex() || me.Username != "alice" {
+		t.Errorf("GET /me = %+v, want %s alice", me, user.ID.Hex())
+	}
+
+	// A second login finds the linked account instead of creating another
+	result = loginWithProvider(t, s)
+	if result.User == nil || result.User.ID != user.ID.Hex() {
+		t.Errorf("second login = %+v, want user %s", result.User, user.ID.Hex())
+	}
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RescoreService revokes solves, resets challenges and recomputes solve counts,
+// solve positions and team scores from the correct submissions. Every operation
+// can run as a dry run that only reports what would change.
+type RescoreService struct {
+	submissionRepo *repositories.SubmissionRepository
+	challengeRepo  *repositories.ChallengeRepository
+	teamRepo       *repositories.TeamRepository
+	userRepo       *repositories.UserRepository
+	hintUnlockRepo *repositories.HintUnlockRepository
+	solveRepo      *repositories.SolveRepository
+	awardRepo      *repositories.AwardRepository
+}
+
+func NewRescoreService(
+	submissionRepo *repositories.SubmissionRepository,
+	challengeRepo *repositories.ChallengeRepository,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+	hintUnlockRepo *repositories.HintUnlockRepository,
+	solveRepo *repositories.SolveRepository,
+	awardRepo *repositories.AwardRepository,
+) *RescoreService {
+	return &RescoreService{
+		submissionRepo: submissionRepo,
+		challengeRepo:  challengeRepo,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		hintUnlockRepo: hintUnlockRepo,
+		solveRepo:      solveRepo,
+		awardRepo:      awardRepo,
+	}
+}
+
+// SolveCountChange is a challenge whose solve count changes
+type SolveCountChange struct {
+	ChallengeID    string `json:"challenge_id"`
+	ChallengeTitle string `json:"challenge_title"`
+	Old            int    `json:"old"`
+	New            int    `json:"new"`
+	OldPoints      int    `json:"old_points"`
+	NewPoints      int    `json:"new_points"`
+}
+
+// Kinds of solve change
+const (
+	SolveAdded   = "added"
+	SolveRemoved = "removed"
+	SolveUpdated = "updated"
+)
+
+// SolveChange is a solve that is added, removed, or whose position or bonus changes
+type SolveChange struct {
+	Change         string `json:"change"`
+	SolveID        string `json:"solve_id,omitempty"`
+	ChallengeID    string `json:"challenge_id"`
+	ChallengeTitle string `json:"challenge_title"`
+	OwnerID        string `json:"owner_id"`
+	OwnerName      string `json:"owner_name"`
+	OldPosition    int    `json:"old_position,omitempty"`
+	NewPosition    int    `json:"new_position,omitempty"`
+	OldBonus       int    `json:"old_bonus,omitempty"`
+	NewBonus       int    `json:"new_bonus,omitempty"`
+}
+
+// TeamScoreChange is a team whose stored score changes
+type TeamScoreChange struct {
+	TeamID   string `json:"team_id"`
+	TeamName string `json:"team_name"`
+	Old      int    `json:"old"`
+	New      int    `json:"new"`
+}
+
+// RescoreResult is the diff of a rescore operation; with DryRun nothing was written
+type RescoreResult struct {
+	DryRun             bool               `json:"dry_run"`
+	RevokedSubmissions int                `json:"revoked_submissions"`
+	SolveCounts        []SolveCountChange `json:"solve_counts"`
+	Solves             []SolveChange      `json:"solves"`
+	TeamScores         []TeamScoreChange  `json:"team_scores"`
+}
+
+// RevokeSolve revokes a solve: the owner's correct submissions of the challenge are
+// marked revoked, then the event is rescored
+func (s *RescoreService) RevokeSolve(solveID string, adminID primitive.ObjectID, dryRun bool) (*RescoreResult, error) {
+	solve, err := s.solveRepo.GetSolveByID(solveID)
+	if err != nil {
+		return nil, errors.New("solve not found")
+	}
+
+	return s.rescore(adminID, dryRun, func(sub *models.Submission) bool {
+		return sub.ChallengeID == solve.ChallengeID && submissionOwner(sub) == solve.OwnerID
+	})
+}
+
+// ResetChallenge revokes every solve of a challenge, then rescores the event
+func (s *RescoreService) ResetChallenge(challengeID string, adminID primitive.ObjectID, dryRun bool) (*RescoreResult, error) {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, errors.New("challenge not found")
+	}
+
+	return s.rescore(adminID, dryRun, func(sub *models.Submission) bool {
+		return sub.ChallengeID == challenge.ID
+	})
+}
+
+// Recompute rebuilds solve counts, solve positions and team scores from the
+// correct submissions without revoking anything
+func (s *RescoreService) Recompute(adminID primitive.ObjectID, dryRun bool) (*RescoreResult, error) {
+	return s.rescore(adminID, dryRun, nil)
+}
+
+// submissionOwner returns the team of a submission, or its user when they had no team
+func submissionOwner(sub *models.Submission) primitive.ObjectID {
+	if !sub.TeamID.IsZero() {
+		return sub.TeamID
+	}
+	return sub.UserID
+}
+
+// rescore revokes the correct submissions selected by revoke (if any) and works out
+// the solves, solve counts and team scores implied by the remaining ones
+func (s *RescoreService) rescore(adminID primitive.ObjectID, dryRun bool, revoke func(*models.Submission) bool) (*RescoreResult, error) {
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := s.submissionRepo.GetAllCorrectSubmissions()
+	if err != nil {
+		return nil, err
+	}
+	solves, err := s.solveRepo.GetAllSolves()
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.teamRepo.GetAllTeamsWithScores()
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	unlocks, err := s.hintUnlockRepo.GetAllUnlocks()
+	if err != nil {
+		return nil, err
+	}
+	awards, err := s.awardRepo.GetAllAwards()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &repositories.RescoreChanges{
+		RevokedBy:   adminID,
+		SolveCounts: make(map[primitive.ObjectID]int),
+		TeamScores:  make(map[primitive.ObjectID]int),
+	}
+	result := &RescoreResult{
+		DryRun:      dryRun,
+		SolveCounts: []SolveCountChange{},
+		Solves:      []SolveChange{},
+		TeamScores:  []TeamScoreChange{},
+	}
+
+	challengeMap := make(map[primitive.ObjectID]*models.Challenge)
+	for i := range challenges {
+		challengeMap[challenges[i].ID] = &challenges[i]
+	}
+	ownerNames := make(map[primitive.ObjectID]string)
+	for _, u := range users {
+		ownerNames[u.ID] = u.Username
+	}
+	for _, t := range teams {
+		ownerNames[t.ID] = t.Name
+	}
+
+	type solveKey struct {
+		challenge primitive.ObjectID
+		owner     primitive.ObjectID
+	}
+	existing := make(map[solveKey]models.Solve)
+	for _, solve := range solves {
+		if _, exists := challengeMap[solve.ChallengeID]; exists {
+			existing[solveKey{solve.ChallengeID, solve.OwnerID}] = solve
+		}
+	}
+
+	// The earliest remaining correct submission of each owner is their solve
+	wanted := make(map[solveKey]models.Solve)
+	for i := range submissions {
+		sub := &submissions[i]
+		if revoke != nil && revoke(sub) {
+			changes.RevokeSubmissions = append(changes.RevokeSubmissions, sub.ID)
+			continue
+		}
+		if _, exists := challengeMap[sub.ChallengeID]; !exists {
+			continue
+		}
+		key := solveKey{sub.ChallengeID, submissionOwner(sub)}
+		if w, seen := wanted[key]; seen && !sub.Timestamp.Before(w.SolvedAt) {
+			continue
+		}
+		wanted[key] = models.Solve{
+			ID:          existing[key].ID,
+			ChallengeID: sub.ChallengeID,
+			OwnerID:     key.owner,
+			TeamID:      sub.TeamID,
+			UserID:      sub.UserID,
+			SolvedAt:    sub.Timestamp,
+		}
+	}
+	result.RevokedSubmissions = len(changes.RevokeSubmissions)
+
+	// Number each challenge's solves in solve order, keeping the previous order on ties
+	byChallenge := make(map[primitive.ObjectID][]models.Solve)
+	for _, solve := range wanted {
+		byChallenge[solve.ChallengeID] = append(byChallenge[solve.ChallengeID], solve)
+	}
+	previousPosition := func(solve models.Solve) int {
+		if old, exists := existing[solveKey{solve.ChallengeID, solve.OwnerID}]; exists && old.Position > 0 {
+			return old.Position
+		}
+		return int(^uint(0) >> 1)
+	}
+
+	newPoints := make(map[primitive.ObjectID]int)
+	for _, challenge := range challenges {
+		list := byChallenge[challenge.ID]
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].SolvedAt.Equal(list[j].SolvedAt) {
+				return list[i].SolvedAt.Before(list[j].SolvedAt)
+			}
+			if pi, pj := previousPosition(list[i]), previousPosition(list[j]); pi != pj {
+				return pi < pj
+			}
+			return list[i].OwnerID.Hex() < list[j].OwnerID.Hex()
+		})
+		for i := range list {
+			list[i].Position = i + 1
+			list[i].Bonus = challenge.BloodBonus(i + 1)
+			wanted[solveKey{challenge.ID, list[i].OwnerID}] = list[i]
+		}
+
+		newPoints[challenge.ID] = challenge.PointsAt(len(list))
+		if len(list) != challenge.SolveCount {
+			changes.SolveCounts[challenge.ID] = len(list)
+			result.SolveCounts = append(result.SolveCounts, SolveCountChange{
+				ChallengeID:    challenge.ID.Hex(),
+				ChallengeTitle: challenge.Title,
+				Old:            challenge.SolveCount,
+				New:            len(list),
+				OldPoints:      challenge.CurrentPoints(),
+				NewPoints:      newPoints[challenge.ID],
+			})
+		}
+	}
+
+	solveChange := func(change string, solve models.Solve) SolveChange {
+		return SolveChange{
+			Change:         change,
+			ChallengeID:    solve.ChallengeID.Hex(),
+			ChallengeTitle: challengeMap[solve.ChallengeID].Title,
+			OwnerID:        solve.OwnerID.Hex(),
+			OwnerName:      ownerNames[solve.OwnerID],
+		}
+	}
+	for key, old := range existing {
+		if _, kept := wanted[key]; kept {
+			continue
+		}
+		changes.DeleteSolves = append(changes.DeleteSolves, old.ID)
+		change := solveChange(SolveRemoved, old)
+		change.SolveID = old.ID.Hex()
+		change.OldPosition = old.Position
+		change.OldBonus = old.Bonus
+		result.Solves = append(result.Solves, change)
+	}
+	for key, solve := range wanted {
+		old, exists := existing[key]
+		switch {
+		case !exists:
+			changes.InsertSolves = append(changes.InsertSolves, solve)
+			change := solveChange(SolveAdded, solve)
+			change.NewPosition = solve.Position
+			change.NewBonus = solve.Bonus
+			result.Solves = append(result.Solves, change)
+		case old.Position != solve.Position || old.Bonus != solve.Bonus:
+			changes.UpdateSolves = append(changes.UpdateSolves, solve)
+			change := solveChange(SolveUpdated, solve)
+			change.SolveID = old.ID.Hex()
+			change.OldPosition = old.Position
+			change.NewPosition = solve.Position
+			change.OldBonus = old.Bonus
+			change.NewBonus = solve.Bonus
+			result.Solves = append(result.Solves, change)
+		}
+	}
+	sort.Slice(result.Solves, func(i, j int) bool {
+		a, b := result.Solves[i], result.Solves[j]
+		if a.ChallengeTitle != b.ChallengeTitle {
+			return a.ChallengeTitle < b.ChallengeTitle
+		}
+		return a.OwnerName < b.OwnerName
+	})
+
+	// Team scores match the live team scoreboard: solves at the new challenge values,
+	// blood bonuses, hint costs and awards
+	scores := make(map[primitive.ObjectID]int)
+	for _, solve := range wanted {
+		if !solve.TeamID.IsZero() {
+			scores[solve.TeamID] += newPoints[solve.ChallengeID] + solve.Bonus
+		}
+	}
+	for _, u := range unlocks {
+		if _, exists := challengeMap[u.ChallengeID]; exists && !u.TeamID.IsZero() {
+			scores[u.TeamID] -= u.Cost
+		}
+	}
+	for _, award := range awards {
+		if !award.TeamID.IsZero() {
+			scores[award.TeamID] += award.Points
+		}
+	}
+	for _, team := range teams {
+		if scores[team.ID] == team.Score {
+			continue
+		}
+		changes.TeamScores[team.ID] = scores[team.ID]
+		result.TeamScores = append(result.TeamScores, TeamScoreChange{
+			TeamID:   team.ID.Hex(),
+			TeamName: team.Name,
+			Old:      team.Score,
+			New:      scores[team.ID],
+		})
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := s.solveRepo.ApplyRescore(changes); err != nil {
+		return nil, err
+	}
+	invalidateScoreboardCache()
+
+	return result, nil
+}
+
+// SolveInfo is a solve of a challenge with the solver's names, for the admin view
+type SolveInfo struct {
+	ID        string `json:"id"`
+	Position  int    `json:"position"`
+	Bonus     int    `json:"bonus,omitempty"`
+	OwnerID   string `json:"owner_id"`
+	OwnerName string `json:"owner_name"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	TeamID    string `json:"team_id,omitempty"`
+	SolvedAt  string `json:"solved_at"`
+}
+
+// GetChallengeSolves lists the solves of a challenge in solve order, so admins can pick one to revoke
+func (s *RescoreService) GetChallengeSolves(challengeID string) ([]SolveInfo, error) {
+	challenge, err := s.challengeRepo.GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, errors.New("challenge not found")
+	}
+
+	solves, err := s.solveRepo.GetChallengeSolves(challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SolveInfo, 0, len(solves))
+	for _, solve := range solves {
+		info := SolveInfo{
+			ID:        solve.ID.Hex(),
+			Position:  solve.Position,
+			Bonus:     solve.Bonus,
+			OwnerID:   solve.OwnerID.Hex(),
+			OwnerName: "Unknown",
+			UserID:    solve.UserID.Hex(),
+			Username:  "Unknown",
+			SolvedAt:  solve.SolvedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if user, err := s.userRepo.FindByID(solve.UserID.Hex()); err == nil {
+			info.Username = user.Username
+		}
+		if !solve.TeamID.IsZero() {
+			info.TeamID = solve.TeamID.Hex()
+			if team, err := s.teamRepo.FindTeamByID(solve.TeamID.Hex()); err == nil {
+				info.OwnerName = team.Name
+			}
+		} else {
+			info.OwnerName = info.Username
+		}
+		result = append(result, info)
+	}
+
+	return result, nil
+}
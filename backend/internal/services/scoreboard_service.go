@@ -7,23 +7,58 @@ import (
 	"time"
 
 	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
 	"github.com/go-ctf-platform/backend/internal/repositories"
 )
 
+// Cached scoreboard views; the frozen variants hold the view at the freeze time
+const (
+	scoreboardCacheKey             = "scoreboard"
+	scoreboardFrozenCacheKey       = "scoreboard_frozen"
+	teamScoreboardCacheKey         = "team_scoreboard"
+	teamScoreboardFrozenCacheKey   = "team_scoreboard_frozen"
+	teamScoreHistoryCacheKey       = "team_scoreboard_history"
+	teamScoreHistoryFrozenCacheKey = "team_scoreboard_history_frozen"
+)
+
+// scoreboardCacheKeys lists every cached scoreboard view
+var scoreboardCacheKeys = []string{
+	scoreboardCacheKey,
+	scoreboardFrozenCacheKey,
+	teamScoreboardCacheKey,
+	teamScoreboardFrozenCacheKey,
+	teamScoreHistoryCacheKey,
+	teamScoreHistoryFrozenCacheKey,
+}
+
+// invalidateScoreboardCache drops every cached scoreboard view after a score change
+func invalidateScoreboardCache() {
+	if database.RDB != nil {
+		database.RDB.Del(context.Background(), scoreboardCacheKeys...)
+	}
+}
+
 type ScoreboardService struct {
 	userRepo       *repositories.UserRepository
 	submissionRepo *repositories.SubmissionRepository
 	challengeRepo  *repositories.ChallengeRepository
 	teamRepo       *repositories.TeamRepository
+	hintUnlockRepo *repositories.HintUnlockRepository
+	solveRepo      *repositories.SolveRepository
+	awardRepo      *repositories.AwardRepository
+	eventService   *EventService
 }
 
 type UserScore struct {
-	Username string `json:"username"`
-	Score    int    `json:"score"`
-	TeamName string `json:"team_name,omitempty"`
+	Rank        int       `json:"rank"`
+	Username    string    `json:"username"`
+	Score       int       `json:"score"`
+	TeamName    string    `json:"team_name,omitempty"`
+	LastSolveAt time.Time `json:"last_solve_at,omitempty"` // Tie-breaker: earlier ranks higher
 }
 
 type TeamScore struct {
+	Rank        int       `json:"rank"`
 	ID          string    `json:"id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
@@ -32,6 +67,30 @@ type TeamScore struct {
 	LeaderID    string    `json:"leader_id,omitempty"`
 	CreatedAt   time.Time `json:"created_at,omitempty"`
 	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	LastSolveAt time.Time `json:"last_solve_at,omitempty"` // Tie-breaker: earlier ranks higher
+}
+
+// compareRanking orders by score (highest first), then by the earliest last solve,
+// with entries that never solved anything last. It returns 0 when both share a rank.
+func compareRanking(scoreA int, lastA time.Time, scoreB int, lastB time.Time) int {
+	if scoreA != scoreB {
+		if scoreA > scoreB {
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case lastA.Equal(lastB):
+		return 0
+	case lastA.IsZero():
+		return 1
+	case lastB.IsZero():
+		return -1
+	case lastA.Before(lastB):
+		return -1
+	default:
+		return 1
+	}
 }
 
 func NewScoreboardService(
@@ -39,18 +98,125 @@ func NewScoreboardService(
 	submissionRepo *repositories.SubmissionRepository,
 	challengeRepo *repositories.ChallengeRepository,
 	teamRepo *repositories.TeamRepository,
+	hintUnlockRepo *repositories.HintUnlockRepository,
+	solveRepo *repositories.SolveRepository,
+	awardRepo *repositories.AwardRepository,
+	eventService *EventService,
 ) *ScoreboardService {
 	return &ScoreboardService{
 		userRepo:       userRepo,
 		submissionRepo: submissionRepo,
 		challengeRepo:  challengeRepo,
 		teamRepo:       teamRepo,
+		hintUnlockRepo: hintUnlockRepo,
+		solveRepo:      solveRepo,
+		awardRepo:      awardRepo,
+		eventService:   eventService,
+	}
+}
+
+// submissionsBefore keeps the submissions made before cutoff; a zero cutoff keeps all of them
+func submissionsBefore(submissions []models.Submission, cutoff time.Time) []models.Submission {
+	if cutoff.IsZero() {
+		return submissions
+	}
+	filtered := make([]models.Submission, 0, len(submissions))
+	for _, sub := range submissions {
+		if sub.Timestamp.Before(cutoff) {
+			filtered = append(filtered, sub)
+		}
 	}
+	return filtered
 }
 
+// challengePointsAt returns the points of each challenge. With a cutoff, points are
+// computed from the number of teams/users that had solved it by then.
+func challengePointsAt(challenges []models.Challenge, submissions []models.Submission, cutoff time.Time) map[string]int {
+	challengePoints := make(map[string]int)
+	if cutoff.IsZero() {
+		for _, c := range challenges {
+			challengePoints[c.ID.Hex()] = c.CurrentPoints()
+		}
+		return challengePoints
+	}
+
+	solvers := make(map[string]map[string]bool)
+	for _, sub := range submissions {
+		cid := sub.ChallengeID.Hex()
+		owner := sub.UserID.Hex()
+		if !sub.TeamID.IsZero() {
+			owner = sub.TeamID.Hex()
+		}
+		if solvers[cid] == nil {
+			solvers[cid] = make(map[string]bool)
+		}
+		solvers[cid][owner] = true
+	}
+
+	for _, c := range challenges {
+		c.SolveCount = len(solvers[c.ID.Hex()])
+		challengePoints[c.ID.Hex()] = c.CurrentPoints()
+	}
+	return challengePoints
+}
+
+// bonusSolves returns the solves of existing challenges that earned a blood bonus before cutoff
+func (s *ScoreboardService) bonusSolves(challengePoints map[string]int, cutoff time.Time) ([]models.Solve, error) {
+	solves, err := s.solveRepo.GetAllSolves()
+	if err != nil {
+		return nil, err
+	}
+
+	bonuses := make([]models.Solve, 0)
+	for _, solve := range solves {
+		if solve.Bonus == 0 {
+			continue
+		}
+		if _, exists := challengePoints[solve.ChallengeID.Hex()]; !exists {
+			continue
+		}
+		if !cutoff.IsZero() && !solve.SolvedAt.Before(cutoff) {
+			continue
+		}
+		bonuses = append(bonuses, solve)
+	}
+	return bonuses, nil
+}
+
+// awardsBefore returns the manual awards and penalties made before cutoff
+func (s *ScoreboardService) awardsBefore(cutoff time.Time) ([]models.Award, error) {
+	awards, err := s.awardRepo.GetAllAwards()
+	if err != nil {
+		return nil, err
+	}
+	if cutoff.IsZero() {
+		return awards, nil
+	}
+	filtered := make([]models.Award, 0, len(awards))
+	for _, award := range awards {
+		if award.CreatedAt.Before(cutoff) {
+			filtered = append(filtered, award)
+		}
+	}
+	return filtered, nil
+}
+
+// GetScoreboard returns the public user scoreboard, frozen at the freeze time if one has passed
 func (s *ScoreboardService) GetScoreboard() ([]UserScore, error) {
+	return s.getScoreboard(s.eventService.FreezeCutoff())
+}
+
+// GetLiveScoreboard returns the user scoreboard ignoring any freeze (admin view)
+func (s *ScoreboardService) GetLiveScoreboard() ([]UserScore, error) {
+	return s.getScoreboard(time.Time{})
+}
+
+func (s *ScoreboardService) getScoreboard(cutoff time.Time) ([]UserScore, error) {
 	ctx := context.Background()
-	cacheKey := "scoreboard"
+	cacheKey := scoreboardCacheKey
+	if !cutoff.IsZero() {
+		cacheKey = scoreboardFrozenCacheKey
+	}
 
 	// Try to get from Redis
 	if database.RDB != nil {
@@ -68,24 +234,68 @@ func (s *ScoreboardService) GetScoreboard() ([]UserScore, error) {
 	if err != nil {
 		return nil, err
 	}
+	submissions = submissionsBefore(submissions, cutoff)
 
 	challenges, err := s.challengeRepo.GetAllChallenges()
 	if err != nil {
 		return nil, err
 	}
 
-	challengePoints := make(map[string]int)
-	for _, c := range challenges {
-		challengePoints[c.ID.Hex()] = c.CurrentPoints()
-	}
+	challengePoints := challengePointsAt(challenges, submissions, cutoff)
 
 	userScores := make(map[string]int)
+	userLastSolves := make(map[string]time.Time)
 
-	// Sum points for every user's solve
+	// Sum points for every user's solve; concurrent submissions can leave a user
+	// more than one correct submission for a challenge, which only counts once
+	solved := make(map[string]bool)
 	for _, sub := range submissions {
 		userID := sub.UserID.Hex()
-		points := challengePoints[sub.ChallengeID.Hex()]
+		key := userID + ":" + sub.ChallengeID.Hex()
+		if solved[key] {
+			continue
+		}
+		solved[key] = true
+		points, exists := challengePoints[sub.ChallengeID.Hex()]
 		userScores[userID] += points
+		if exists && sub.Timestamp.After(userLastSolves[userID]) {
+			userLastSolves[userID] = sub.Timestamp
+		}
+	}
+
+	// Add blood bonuses to the user who submitted the solve
+	bonuses, err := s.bonusSolves(challengePoints, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, solve := range bonuses {
+		userScores[solve.UserID.Hex()] += solve.Bonus
+	}
+
+	// Deduct hints purchased by each user
+	unlocks, err := s.hintUnlockRepo.GetAllUnlocks()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unlocks {
+		if _, exists := challengePoints[u.ChallengeID.Hex()]; !exists {
+			continue
+		}
+		if !cutoff.IsZero() && !u.UnlockedAt.Before(cutoff) {
+			continue
+		}
+		userScores[u.UserID.Hex()] -= u.Cost
+	}
+
+	// Apply manual awards and penalties given to users
+	awards, err := s.awardsBefore(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, award := range awards {
+		if !award.UserID.IsZero() {
+			userScores[award.UserID.Hex()] += award.Points
+		}
 	}
 
 	// Fetch all users to map ID to Username
@@ -117,19 +327,26 @@ func (s *ScoreboardService) GetScoreboard() ([]UserScore, error) {
 			username = "Unknown"
 		}
 		scores = append(scores, UserScore{
-			Username: username,
-			Score:    score,
-			TeamName: userTeamMap[uid],
+			Username:    username,
+			Score:       score,
+			TeamName:    userTeamMap[uid],
+			LastSolveAt: userLastSolves[uid],
 		})
 	}
 
-	// Sort scores by score descending
+	// Sort scores by score descending, ties broken by the earliest last solve
 	sort.Slice(scores, func(i, j int) bool {
-		if scores[i].Score == scores[j].Score {
-			return scores[i].Username < scores[j].Username
+		if c := compareRanking(scores[i].Score, scores[i].LastSolveAt, scores[j].Score, scores[j].LastSolveAt); c != 0 {
+			return c < 0
 		}
-		return scores[i].Score > scores[j].Score
+		return scores[i].Username < scores[j].Username
 	})
+	for i := range scores {
+		scores[i].Rank = i + 1
+		if i > 0 && compareRanking(scores[i-1].Score, scores[i-1].LastSolveAt, scores[i].Score, scores[i].LastSolveAt) == 0 {
+			scores[i].Rank = scores[i-1].Rank
+		}
+	}
 
 	// Store in Redis
 	if database.RDB != nil {
@@ -142,9 +359,22 @@ func (s *ScoreboardService) GetScoreboard() ([]UserScore, error) {
 	return scores, nil
 }
 
+// GetTeamScoreboard returns the public team scoreboard, frozen at the freeze time if one has passed
 func (s *ScoreboardService) GetTeamScoreboard() ([]TeamScore, error) {
+	return s.getTeamScoreboard(s.eventService.FreezeCutoff())
+}
+
+// GetLiveTeamScoreboard returns the team scoreboard ignoring any freeze (admin view)
+func (s *ScoreboardService) GetLiveTeamScoreboard() ([]TeamScore, error) {
+	return s.getTeamScoreboard(time.Time{})
+}
+
+func (s *ScoreboardService) getTeamScoreboard(cutoff time.Time) ([]TeamScore, error) {
 	ctx := context.Background()
-	cacheKey := "team_scoreboard"
+	cacheKey := teamScoreboardCacheKey
+	if !cutoff.IsZero() {
+		cacheKey = teamScoreboardFrozenCacheKey
+	}
 
 	// Try to get from Redis
 	if database.RDB != nil {
@@ -168,18 +398,16 @@ func (s *ScoreboardService) GetTeamScoreboard() ([]TeamScore, error) {
 		return nil, err
 	}
 
-	challengePoints := make(map[string]int)
-	for _, c := range challenges {
-		challengePoints[c.ID.Hex()] = c.CurrentPoints()
-	}
-
 	submissions, err := s.submissionRepo.GetAllCorrectSubmissions()
 	if err != nil {
 		return nil, err
 	}
+	submissions = submissionsBefore(submissions, cutoff)
 
-	// Map TeamID -> Set of ChallengeIDs solved
-	teamSolves := make(map[string]map[string]bool)
+	challengePoints := challengePointsAt(challenges, submissions, cutoff)
+
+	// Map TeamID -> ChallengeID -> time of the team's first solve
+	teamSolves := make(map[string]map[string]time.Time)
 	for _, sub := range submissions {
 		if sub.TeamID.IsZero() {
 			continue
@@ -188,21 +416,77 @@ func (s *ScoreboardService) GetTeamScoreboard() ([]TeamScore, error) {
 		cid := sub.ChallengeID.Hex()
 		
 		if teamSolves[tid] == nil {
-			teamSolves[tid] = make(map[string]bool)
+			teamSolves[tid] = make(map[string]time.Time)
+		}
+		if t, seen := teamSolves[tid][cid]; !seen || sub.Timestamp.Before(t) {
+			teamSolves[tid][cid] = sub.Timestamp
+		}
+	}
+
+	// Map TeamID -> total blood bonuses
+	teamBonuses := make(map[string]int)
+	bonuses, err := s.bonusSolves(challengePoints, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, solve := range bonuses {
+		if !solve.TeamID.IsZero() {
+			teamBonuses[solve.TeamID.Hex()] += solve.Bonus
+		}
+	}
+
+	// Map TeamID -> total cost of unlocked hints
+	teamHintCosts := make(map[string]int)
+	unlocks, err := s.hintUnlockRepo.GetAllUnlocks()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unlocks {
+		if u.TeamID.IsZero() {
+			continue
+		}
+		if _, exists := challengePoints[u.ChallengeID.Hex()]; !exists {
+			continue
+		}
+		if !cutoff.IsZero() && !u.UnlockedAt.Before(cutoff) {
+			continue
+		}
+		teamHintCosts[u.TeamID.Hex()] += u.Cost
+	}
+
+	// Map TeamID -> total of manual awards and penalties
+	teamAwards := make(map[string]int)
+	awards, err := s.awardsBefore(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, award := range awards {
+		if !award.TeamID.IsZero() {
+			teamAwards[award.TeamID.Hex()] += award.Points
 		}
-		teamSolves[tid][cid] = true
 	}
 
 	var scores []TeamScore
 	for _, team := range teams {
 		tid := team.ID.Hex()
 		totalScore := 0
+		var lastSolve time.Time
 		
 		if solves, exists := teamSolves[tid]; exists {
-			for cid := range solves {
-				totalScore += challengePoints[cid]
+			for cid, solvedAt := range solves {
+				points, exists := challengePoints[cid]
+				if !exists {
+					continue
+				}
+				totalScore += points
+				if solvedAt.After(lastSolve) {
+					lastSolve = solvedAt
+				}
 			}
 		}
+		totalScore += teamBonuses[tid]
+		totalScore -= teamHintCosts[tid]
+		totalScore += teamAwards[tid]
 
 		memberIDs := make([]string, len(team.MemberIDs))
 		for i, mid := range team.MemberIDs {
@@ -218,16 +502,23 @@ func (s *ScoreboardService) GetTeamScoreboard() ([]TeamScore, error) {
 			LeaderID:    team.LeaderID.Hex(),
 			CreatedAt:   team.CreatedAt,
 			UpdatedAt:   team.UpdatedAt,
+			LastSolveAt: lastSolve,
 		})
 	}
 
-	// Sort scores by score descending
+	// Sort scores by score descending, ties broken by the earliest last solve
 	sort.Slice(scores, func(i, j int) bool {
-		if scores[i].Score == scores[j].Score {
-			return scores[i].Name < scores[j].Name
+		if c := compareRanking(scores[i].Score, scores[i].LastSolveAt, scores[j].Score, scores[j].LastSolveAt); c != 0 {
+			return c < 0
 		}
-		return scores[i].Score > scores[j].Score
+		return scores[i].Name < scores[j].Name
 	})
+	for i := range scores {
+		scores[i].Rank = i + 1
+		if i > 0 && compareRanking(scores[i-1].Score, scores[i-1].LastSolveAt, scores[i].Score, scores[i].LastSolveAt) == 0 {
+			scores[i].Rank = scores[i-1].Rank
+		}
+	}
 
 	// Store in Redis
 	if database.RDB != nil {
@@ -238,4 +529,213 @@ func (s *ScoreboardService) GetTeamScoreboard() ([]TeamScore, error) {
 	}
 
 	return scores, nil
-}
\ No newline at end of file
+}
+
+// ScorePoint is a team's cumulative score right after a scoring event
+type ScorePoint struct {
+	Time  time.Time `json:"time"`
+	Score int       `json:"score"`
+}
+
+// TeamHistory is the score-over-time series of one team, for scoreboard graphs
+type TeamHistory struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Score  int          `json:"score"`
+	Points []ScorePoint `json:"points"`
+}
+
+// GetTeamScoreHistory returns the cumulative score timeline of the top teams,
+// frozen at the freeze time like the public team scoreboard
+func (s *ScoreboardService) GetTeamScoreHistory(top int) ([]TeamHistory, error) {
+	history, err := s.getTeamScoreHistory(s.eventService.FreezeCutoff())
+	if err != nil {
+		return nil, err
+	}
+	if top > 0 && len(history) > top {
+		history = history[:top]
+	}
+	return history, nil
+}
+
+// getTeamScoreHistory builds the timeline of every team, in scoreboard order.
+// Each solve is worth the challenge's current (decayed) value, so the final
+// point of every series matches the team scoreboard.
+func (s *ScoreboardService) getTeamScoreHistory(cutoff time.Time) ([]TeamHistory, error) {
+	ctx := context.Background()
+	cacheKey := teamScoreHistoryCacheKey
+	if !cutoff.IsZero() {
+		cacheKey = teamScoreHistoryFrozenCacheKey
+	}
+
+	// Try to get from Redis
+	if database.RDB != nil {
+		val, err := database.RDB.Get(ctx, cacheKey).Result()
+		if err == nil {
+			var history []TeamHistory
+			if err := json.Unmarshal([]byte(val), &history); err == nil {
+				return history, nil
+			}
+		}
+	}
+
+	scores, err := s.getTeamScoreboard(cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := s.submissionRepo.GetAllCorrectSubmissions()
+	if err != nil {
+		return nil, err
+	}
+	submissions = submissionsBefore(submissions, cutoff)
+
+	challengePoints := challengePointsAt(challenges, submissions, cutoff)
+
+	type scoreEvent struct {
+		time  time.Time
+		delta int
+	}
+
+	// First solve time of each challenge per team
+	firstSolves := make(map[string]map[string]time.Time)
+	for _, sub := range submissions {
+		if sub.TeamID.IsZero() {
+			continue
+		}
+		cid := sub.ChallengeID.Hex()
+		if _, exists := challengePoints[cid]; !exists {
+			continue
+		}
+		tid := sub.TeamID.Hex()
+		if firstSolves[tid] == nil {
+			firstSolves[tid] = make(map[string]time.Time)
+		}
+		if t, seen := firstSolves[tid][cid]; !seen || sub.Timestamp.Before(t) {
+			firstSolves[tid][cid] = sub.Timestamp
+		}
+	}
+
+	events := make(map[string][]scoreEvent)
+	for tid, solves := range firstSolves {
+		for cid, t := range solves {
+			events[tid] = append(events[tid], scoreEvent{time: t, delta: challengePoints[cid]})
+		}
+	}
+
+	// Blood bonuses are earned at the time of the solve
+	bonuses, err := s.bonusSolves(challengePoints, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, solve := range bonuses {
+		if solve.TeamID.IsZero() {
+			continue
+		}
+		tid := solve.TeamID.Hex()
+		events[tid] = append(events[tid], scoreEvent{time: solve.SolvedAt, delta: solve.Bonus})
+	}
+
+	// Hint purchases lower the score at the time they were unlocked
+	unlocks, err := s.hintUnlockRepo.GetAllUnlocks()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unlocks {
+		if u.TeamID.IsZero() {
+			continue
+		}
+		if _, exists := challengePoints[u.ChallengeID.Hex()]; !exists {
+			continue
+		}
+		if !cutoff.IsZero() && !u.UnlockedAt.Before(cutoff) {
+			continue
+		}
+		tid := u.TeamID.Hex()
+		events[tid] = append(events[tid], scoreEvent{time: u.UnlockedAt, delta: -u.Cost})
+	}
+
+	// Awards and penalties apply at the time they were made
+	awards, err := s.awardsBefore(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, award := range awards {
+		if award.TeamID.IsZero() {
+			continue
+		}
+		tid := award.TeamID.Hex()
+		events[tid] = append(events[tid], scoreEvent{time: award.CreatedAt, delta: award.Points})
+	}
+
+	history := make([]TeamHistory, 0, len(scores))
+	for _, team := range scores {
+		teamEvents := events[team.ID]
+		sort.Slice(teamEvents, func(i, j int) bool {
+			return teamEvents[i].time.Before(teamEvents[j].time)
+		})
+
+		points := make([]ScorePoint, 0, len(teamEvents))
+		total := 0
+		for _, e := range teamEvents {
+			total += e.delta
+			points = append(points, ScorePoint{Time: e.time, Score: total})
+		}
+
+		history = append(history, TeamHistory{
+			ID:     team.ID,
+			Name:   team.Name,
+			Score:  team.Score,
+			Points: points,
+		})
+	}
+
+	// Store in Redis
+	if database.RDB != nil {
+		data, err := json.Marshal(history)
+		if err == nil {
+			database.RDB.Set(ctx, cacheKey, data, 1*time.Minute)
+		}
+	}
+
+	return history, nil
+}
+
+// CTFtimeStanding is one entry of a CTFtime scoreboard upload
+type CTFtimeStanding struct {
+	Pos   int    `json:"pos"`
+	Team  string `json:"team"`
+	Score int    `json:"score"`
+}
+
+// CTFtimeScoreboard is the scoreboard feed format accepted by CTFtime
+type CTFtimeScoreboard struct {
+	Standings []CTFtimeStanding `json:"standings"`
+}
+
+// NewCTFtimeScoreboard converts ranked team scores to the CTFtime feed format
+func NewCTFtimeScoreboard(scores []TeamScore) *CTFtimeScoreboard {
+	standings := make([]CTFtimeStanding, 0, len(scores))
+	for _, team := range scores {
+		standings = append(standings, CTFtimeStanding{
+			Pos:   team.Rank,
+			Team:  team.Name,
+			Score: team.Score,
+		})
+	}
+	return &CTFtimeScoreboard{Standings: standings}
+}
+
+// GetCTFtimeScoreboard returns the public team scoreboard in CTFtime format
+func (s *ScoreboardService) GetCTFtimeScoreboard() (*CTFtimeScoreboard, error) {
+	scores, err := s.GetTeamScoreboard()
+	if err != nil {
+		return nil, err
+	}
+	return NewCTFtimeScoreboard(scores), nil
+}
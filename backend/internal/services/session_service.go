@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/database"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// sessionCacheTTL bounds how long Redis may vouch for a session without asking
+	// MongoDB, so a revocation made without Redis (e.g. by the admin CLI on a host
+	// without it) still takes effect quickly
+	sessionCacheTTL = time.Minute
+	// sessionTouchInterval is how often a session's last-seen time is written
+	sessionTouchInterval = 5 * time.Minute
+	// refreshReuseGrace is how long after a rotation the old refresh token is
+	// rejected without counting as reuse, so two tabs refreshing at the same time
+	// do not sign the device out
+	refreshReuseGrace = 10 * time.Second
+)
+
+var (
+	// ErrSessionRevoked is returned for tokens whose session was revoked or has expired
+	ErrSessionRevoked = errors.New("session expired or revoked")
+	// ErrInvalidRefreshToken is returned for unknown, expired or just-rotated refresh tokens
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when a rotated refresh token is presented
+	// again; the whole session is revoked since the token may have been stolen
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, session revoked")
+)
+
+// ClientInfo describes the device a session is created from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionService tracks signed-in sessions and their refresh tokens in MongoDB,
+// with Redis in front of the per-request session lookup when it is available
+type SessionService struct {
+	sessionRepo      *repositories.SessionRepository
+	refreshTokenRepo *repositories.RefreshTokenRepository
+}
+
+func NewSessionService(sessionRepo *repositories.SessionRepository, refreshTokenRepo *repositories.RefreshTokenRepository) *SessionService {
+	return &SessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+func sessionCacheKey(jti string) string {
+	return "session:" + jti
+}
+
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// CreateSession starts a session for a user, valid for ttl unless refreshed, and
+// returns it with its first refresh token. The session's JTI goes in access tokens.
+func (s *SessionService) CreateSession(userID primitive.ObjectID, twoFactor bool, client ClientInfo, ttl time.Duration) (*models.Session, string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		JTI:        hex.EncodeToString(raw),
+		UserID:     userID,
+		TwoFactor:  twoFactor,
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if err := s.sessionRepo.CreateSession(session); err != nil {
+		return nil, "", err
+	}
+
+	refreshToken, err := s.issueRefreshToken(session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.cache(session)
+	return session, refreshToken, nil
+}
+
+// issueRefreshToken creates the next refresh token of a session, expiring with it
+func (s *SessionService) issueRefreshToken(session *models.Session) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err := s.refreshTokenRepo.CreateRefreshToken(&models.RefreshToken{
+		Hash:       hashRefreshToken(token),
+		SessionJTI: session.JTI,
+		UserID:     session.UserID,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  session.ExpiresAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one and extends its session
+// by ttl. Each token works once: presenting a rotated token again revokes the session.
+func (s *SessionService) RotateRefreshToken(refreshToken string, ttl time.Duration) (*models.Session, string, error) {
+	hash := hashRefreshToken(refreshToken)
+	token, err := s.refreshTokenRepo.FindByHash(hash)
+	if err != nil {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	if token.UsedAt.IsZero() {
+		claimed, err := s.refreshTokenRepo.MarkUsed(hash, now)
+		if err != nil {
+			return nil, "", err
+		}
+		if !claimed {
+			// A concurrent request rotated it first
+			return nil, "", ErrInvalidRefreshToken
+		}
+	} else {
+		if now.Sub(token.UsedAt) < refreshReuseGrace {
+			return nil, "", ErrInvalidRefreshToken
+		}
+		if err := s.revoke([]string{token.SessionJTI}); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	session, err := s.sessionRepo.FindByJTI(token.SessionJTI)
+	if err != nil {
+		return nil, "", ErrSessionRevoked
+	}
+
+	session.ExpiresAt = now.Add(ttl)
+	session.LastSeenAt = now
+	if err := s.sessionRepo.Extend(session.JTI, session.ExpiresAt, session.LastSeenAt); err != nil {
+		return nil, "", err
+	}
+
+	next, err := s.issueRefreshToken(session)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, next, nil
+}
+
+// SessionForRefreshToken returns the JTI of the session a refresh token belongs to
+func (s *SessionService) SessionForRefreshToken(refreshToken string) (string, error) {
+	token, err := s.refreshTokenRepo.FindByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		return "", ErrInvalidRefreshToken
+	}
+	return token.SessionJTI, nil
+}
+
+// cache lets Redis answer ValidateSession for the session for a while
+func (s *SessionService) cache(session *models.Session) {
+	if database.RDB == nil {
+		return
+	}
+	ttl := time.Until(session.ExpiresAt)
+	if ttl > sessionCacheTTL {
+		ttl = sessionCacheTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	database.RDB.Set(context.Background(), sessionCacheKey(session.JTI), session.UserID.Hex(), ttl)
+}
+
+// ValidateSession checks that the session of a token still exists and belongs to the
+// token's user
+func (s *SessionService) ValidateSession(jti, userID string) error {
+	if jti == "" {
+		// Tokens issued before sessions were tracked
+		return ErrSessionRevoked
+	}
+
+	if database.RDB != nil {
+		ctx := context.Background()
+		if owner, err := database.RDB.Get(ctx, sessionCacheKey(jti)).Result(); err == nil {
+			if owner != userID {
+				return ErrSessionRevoked
+			}
+			// Only one request per interval records the last-seen time
+			claimed, err := database.RDB.SetNX(ctx, "session_seen:"+jti, 1, sessionTouchInterval).Result()
+			if err == nil && claimed {
+				s.sessionRepo.Touch(jti, time.Now())
+			}
+			return nil
+		}
+	}
+
+	session, err := s.sessionRepo.FindByJTI(jti)
+	if err != nil || session.UserID.Hex() != userID {
+		return ErrSessionRevoked
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		s.sessionRepo.Touch(jti, time.Now())
+	}
+	s.cache(session)
+	return nil
+}
+
+// GetUserSessions returns a user's active sessions, most recently used first
+func (s *SessionService) GetUserSessions(userID string) ([]models.Session, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	sessions, err := s.sessionRepo.FindByUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if sessions == nil {
+		sessions = []models.Session{}
+	}
+	return sessions, nil
+}
+
+// RevokeUserSession revokes one of a user's sessions by its ID
+func (s *SessionService) RevokeUserSession(userID, sessionID string) error {
+	sessions, err := s.GetUserSessions(userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID.Hex() == sessionID {
+			return s.revoke([]string{session.JTI})
+		}
+	}
+	return errors.New("session not found")
+}
+
+// RevokeSession revokes the session of a token, e.g. on logout
+func (s *SessionService) RevokeSession(jti string) error {
+	if jti == "" {
+		return nil
+	}
+	return s.revoke([]string{jti})
+}
+
+// RevokeAllSessions revokes every session of a user except keepJTI (empty to revoke
+// all) and returns how many were revoked
+func (s *SessionService) RevokeAllSessions(userID primitive.ObjectID, keepJTI string) (int, error) {
+	sessions, err := s.sessionRepo.FindByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	jtis := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if session.JTI != keepJTI {
+			jtis = append(jtis, session.JTI)
+		}
+	}
+	if err := s.revoke(jtis); err != nil {
+		return 0, err
+	}
+	return len(jtis), nil
+}
+
+func (s *SessionService) revoke(jtis []string) error {
+	if len(jtis) == 0 {
+		return nil
+	}
+
+	if err := s.sessionRepo.DeleteByJTIs(jtis); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.DeleteBySessions(jtis); err != nil {
+		return err
+	}
+
+	if database.RDB != nil {
+		keys := make([]string, len(jtis))
+		for i, jti := range jtis {
+			keys[i] = sessionCacheKey(jti)
+		}
+		database.RDB.Del(context.Background(), keys...)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/go-ctf-platform/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page size limits of the submissions browser
+const (
+	DefaultSubmissionPageSize = 50
+	MaxSubmissionPageSize     = 200
+)
+
+// Kinds of flag a wrong submission can match
+const (
+	FlagMatchDynamic        = "dynamic_flag"    // Another team's (or teamless user's) dynamic flag for the same challenge
+	FlagMatchOtherChallenge = "other_challenge" // A static flag of a different challenge
+)
+
+type SubmissionService struct {
+	submissionRepo *repositories.SubmissionRepository
+	challengeRepo  *repositories.ChallengeRepository
+	teamRepo       *repositories.TeamRepository
+	userRepo       *repositories.UserRepository
+}
+
+func NewSubmissionService(
+	submissionRepo *repositories.SubmissionRepository,
+	challengeRepo *repositories.ChallengeRepository,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+) *SubmissionService {
+	return &SubmissionService{
+		submissionRepo: submissionRepo,
+		challengeRepo:  challengeRepo,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// FlagMatch explains what a wrong submission actually was
+type FlagMatch struct {
+	Type           string `json:"type"`
+	OwnerID        string `json:"owner_id,omitempty"`
+	OwnerName      string `json:"owner_name,omitempty"`
+	OwnerIsTeam    bool   `json:"owner_is_team,omitempty"`
+	ChallengeID    string `json:"challenge_id,omitempty"`
+	ChallengeTitle string `json:"challenge_title,omitempty"`
+}
+
+// SubmissionInfo is a submission with names resolved for the admin view
+type SubmissionInfo struct {
+	ID             string      `json:"id"`
+	UserID         string      `json:"user_id"`
+	Username       string      `json:"username"`
+	TeamID         string      `json:"team_id,omitempty"`
+	TeamName       string      `json:"team_name,omitempty"`
+	ChallengeID    string      `json:"challenge_id"`
+	ChallengeTitle string      `json:"challenge_title"`
+	FlagHash       string      `json:"flag_hash"`
+	IsCorrect      bool        `json:"is_correct"`
+	Timestamp      string      `json:"timestamp"`
+	RevokedAt      string      `json:"revoked_at,omitempty"`
+	Matches        []FlagMatch `json:"matches,omitempty"`
+}
+
+// SubmissionPage is one page of the submissions browser; NextCursor is empty on the last page
+type SubmissionPage struct {
+	Submissions []SubmissionInfo `json:"submissions"`
+	NextCursor  string           `json:"next_cursor,omitempty"`
+}
+
+// EncodeSubmissionCursor turns a page position into an opaque cursor string
+func EncodeSubmissionCursor(c repositories.SubmissionCursor) string {
+	raw := strconv.FormatInt(c.Timestamp.UnixMilli(), 10) + ":" + c.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSubmissionCursor parses a cursor returned by EncodeSubmissionCursor
+func DecodeSubmissionCursor(cursor string) (*repositories.SubmissionCursor, error) {
+	invalid := errors.New("invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	millis, hexID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, invalid
+	}
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil {
+		return nil, invalid
+	}
+	return &repositories.SubmissionCursor{Timestamp: time.UnixMilli(ms).UTC(), ID: id}, nil
+}
+
+// flagOwner is a team or teamless user that has its own dynamic flags
+type flagOwner struct {
+	id     primitive.ObjectID
+	name   string
+	isTeam bool
+}
+
+// ListSubmissions returns a page of submissions for the admin browser. Wrong
+// submissions are checked against other owners' dynamic flags and other
+// challenges' static flags, which is possible because both are stored as hashes.
+func (s *SubmissionService) ListSubmissions(filter repositories.SubmissionFilter) (*SubmissionPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultSubmissionPageSize
+	}
+	if filter.Limit > MaxSubmissionPageSize {
+		filter.Limit = MaxSubmissionPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra submission to know whether there is a next page
+	filter.Limit++
+	submissions, err := s.submissionRepo.FindSubmissions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SubmissionPage{Submissions: make([]SubmissionInfo, 0, pageSize)}
+	if len(submissions) > pageSize {
+		submissions = submissions[:pageSize]
+		last := submissions[pageSize-1]
+		page.NextCursor = EncodeSubmissionCursor(repositories.SubmissionCursor{Timestamp: last.Timestamp, ID: last.ID})
+	}
+	if len(submissions) == 0 {
+		return page, nil
+	}
+
+	challenges, err := s.challengeRepo.GetAllChallenges()
+	if err != nil {
+		return nil, err
+	}
+	challengeMap := make(map[primitive.ObjectID]*models.Challenge)
+	staticHashes := make(map[string][]*models.Challenge)
+	for i := range challenges {
+		c := &challenges[i]
+		challengeMap[c.ID] = c
+		if c.IsDynamicFlag() {
+			continue
+		}
+		for _, f := range c.StaticFlags() {
+			if f.Hash != "" {
+				staticHashes[f.Hash] = append(staticHashes[f.Hash], c)
+			}
+		}
+	}
+
+	teams, err := s.teamRepo.GetAllTeamsWithScores()
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	teamNames := make(map[primitive.ObjectID]string)
+	inTeam := make(map[primitive.ObjectID]bool)
+	owners := make([]flagOwner, 0, len(teams)+len(users))
+	for _, t := range teams {
+		teamNames[t.ID] = t.Name
+		for _, mid := range t.MemberIDs {
+			inTeam[mid] = true
+		}
+		owners = append(owners, flagOwner{id: t.ID, name: t.Name, isTeam: true})
+	}
+	usernames := make(map[primitive.ObjectID]string)
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+		if !inTeam[u.ID] {
+			owners = append(owners, flagOwner{id: u.ID, name: u.Username})
+		}
+	}
+
+	// Hashes of every owner's dynamic flag, derived once per challenge on the page
+	dynamicHashes := make(map[primitive.ObjectID]map[string]flagOwner)
+	dynamicHashesFor := func(c *models.Challenge) map[string]flagOwner {
+		if hashes, ok := dynamicHashes[c.ID]; ok {
+			return hashes
+		}
+		hashes := make(map[string]flagOwner, len(owners))
+		for _, o := range owners {
+			flag := utils.DeriveDynamicFlag(c.FlagSecret, c.FlagFormat, c.ID.Hex(), o.id.Hex())
+			hashes[utils.HashFlag(flag)] = o
+		}
+		dynamicHashes[c.ID] = hashes
+		return hashes
+	}
+
+	for _, sub := range submissions {
+		info := SubmissionInfo{
+			ID:          sub.ID.Hex(),
+			UserID:      sub.UserID.Hex(),
+			Username:    "Unknown",
+			ChallengeID: sub.ChallengeID.Hex(),
+			FlagHash:    sub.Flag,
+			IsCorrect:   sub.IsCorrect,
+			Timestamp:   sub.Timestamp.Format("2006-01-02T15:04:05Z"),
+		}
+		if sub.IsRevoked() {
+			info.RevokedAt = sub.RevokedAt.Format("2006-01-02T15:04:05Z")
+		}
+		if name, ok := usernames[sub.UserID]; ok {
+			info.Username = name
+		}
+		if !sub.TeamID.IsZero() {
+			info.TeamID = sub.TeamID.Hex()
+			info.TeamName = teamNames[sub.TeamID]
+		}
+
+		challenge := challengeMap[sub.ChallengeID]
+		if challenge != nil {
+			info.ChallengeTitle = challenge.Title
+		}
+
+		if !sub.IsCorrect && !sub.IsRevoked() {
+			ownerID := sub.UserID
+			if !sub.TeamID.IsZero() {
+				ownerID = sub.TeamID
+			}
+			if challenge != nil && challenge.IsDynamicFlag() {
+				if o, ok := dynamicHashesFor(challenge)[sub.Flag]; ok && o.id != ownerID {
+					info.Matches = append(info.Matches, FlagMatch{
+						Type:        FlagMatchDynamic,
+						OwnerID:     o.id.Hex(),
+						OwnerName:   o.name,
+						OwnerIsTeam: o.isTeam,
+					})
+				}
+			}
+			for _, other := range staticHashes[sub.Flag] {
+				if other.ID == sub.ChallengeID {
+					continue
+				}
+				info.Matches = append(info.Matches, FlagMatch{
+					Type:           FlagMatchOtherChallenge,
+					ChallengeID:    other.ID.Hex(),
+					ChallengeTitle: other.Title,
+				})
+			}
+		}
+
+		page.Submissions = append(page.Submissions, info)
+	}
+
+	return page, nil
+}
@@ -1,13 +1,11 @@
 package services
 
 import (
-	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"time"
 
-	"github.com/go-ctf-platform/backend/internal/database"
 	"github.com/go-ctf-platform/backend/internal/models"
 	"github.com/go-ctf-platform/backend/internal/repositories"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -20,6 +18,9 @@ type TeamService struct {
 	emailService   *EmailService
 	submissionRepo *repositories.SubmissionRepository
 	challengeRepo  *repositories.ChallengeRepository
+	hintUnlockRepo *repositories.HintUnlockRepository
+	solveRepo      *repositories.SolveRepository
+	awardRepo      *repositories.AwardRepository
 }
 
 func NewTeamService(
@@ -29,6 +30,9 @@ func NewTeamService(
 	emailService *EmailService,
 	submissionRepo *repositories.SubmissionRepository,
 	challengeRepo *repositories.ChallengeRepository,
+	hintUnlockRepo *repositories.HintUnlockRepository,
+	solveRepo *repositories.SolveRepository,
+	awardRepo *repositories.AwardRepository,
 ) *TeamService {
 	return &TeamService{
 		teamRepo:       teamRepo,
@@ -37,14 +41,9 @@ func NewTeamService(
 		emailService:   emailService,
 		submissionRepo: submissionRepo,
 		challengeRepo:  challengeRepo,
-	}
-}
-
-func (s *TeamService) invalidateScoreboardCache() {
-	if database.RDB != nil {
-		ctx := context.Background()
-		database.RDB.Del(ctx, "scoreboard")
-		database.RDB.Del(ctx, "team_scoreboard")
+		hintUnlockRepo: hintUnlockRepo,
+		solveRepo:      solveRepo,
+		awardRepo:      awardRepo,
 	}
 }
 
@@ -133,6 +132,37 @@ func (s *TeamService) calculateTeamScore(teamID primitive.ObjectID) int {
 			totalScore += challenge.CurrentPoints()
 		}
 	}
+
+	// Add the blood bonuses earned by the team
+	solves, err := s.solveRepo.GetOwnerSolves(teamID)
+	if err == nil {
+		for _, solve := range solves {
+			if solve.Bonus == 0 {
+				continue
+			}
+			if _, err := s.challengeRepo.GetChallengeByID(solve.ChallengeID.Hex()); err == nil {
+				totalScore += solve.Bonus
+			}
+		}
+	}
+
+	// Deduct the cost of hints unlocked by the team
+	unlocks, err := s.hintUnlockRepo.GetTeamUnlocks(teamID)
+	if err == nil {
+		for _, u := range unlocks {
+			if _, err := s.challengeRepo.GetChallengeByID(u.ChallengeID.Hex()); err == nil {
+				totalScore -= u.Cost
+			}
+		}
+	}
+
+	// Apply manual awards and penalties given to the team
+	awards, err := s.awardRepo.GetTeamAwards(teamID)
+	if err == nil {
+		for _, award := range awards {
+			totalScore += award.Points
+		}
+	}
 	return totalScore
 }
 
@@ -188,7 +218,7 @@ func (s *TeamService) UpdateTeam(teamID, leaderID, name, description string) (*m
 		return nil, err
 	}
 
-	s.invalidateScoreboardCache()
+	invalidateScoreboardCache()
 	return team, nil
 }
 
@@ -379,7 +409,7 @@ func (s *TeamService) JoinByInviteCode(userID, inviteCode string) (*models.Team,
 		return nil, err
 	}
 
-	s.invalidateScoreboardCache()
+	invalidateScoreboardCache()
 
 	// Refresh team data
 	return s.teamRepo.FindTeamByID(team.ID.Hex())
@@ -449,7 +479,7 @@ func (s *TeamService) AcceptInvitation(invitationID, userID string) (*models.Tea
 		return nil, err
 	}
 
-	s.invalidateScoreboardCache()
+	invalidateScoreboardCache()
 
 	// Refresh team data
 	return s.teamRepo.FindTeamByID(invitation.TeamID.Hex())
@@ -517,7 +547,7 @@ func (s *TeamService) RemoveMember(teamID, leaderID, memberID string) error {
 
 	err = s.teamRepo.RemoveMemberFromTeam(teamID, memberID)
 	if err == nil {
-		s.invalidateScoreboardCache()
+		invalidateScoreboardCache()
 	}
 	return err
 }
@@ -557,7 +587,7 @@ func (s *TeamService) LeaveTeam(teamID, userID string) error {
 
 	err = s.teamRepo.RemoveMemberFromTeam(teamID, userID)
 	if err == nil {
-		s.invalidateScoreboardCache()
+		invalidateScoreboardCache()
 	}
 	return err
 }
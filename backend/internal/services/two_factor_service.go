@@ -0,0 +1,378 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TOTP parameters (RFC 6238 defaults, understood by every authenticator app)
+const (
+	totpPeriod = 30
+	totpSkew   = 1 // Accept the previous and next code too, for clock drift
+	totpDigits = otp.DigitsSix
+)
+
+const (
+	// RecoveryCodeCount is how many one-time recovery codes are issued at a time
+	RecoveryCodeCount = 10
+	// maxTwoFactorFailures wrong codes in a row lock the second step for twoFactorLockout
+	maxTwoFactorFailures = 5
+	twoFactorLockout     = 5 * time.Minute
+)
+
+var (
+	// ErrInvalidTwoFactorCode is returned for a wrong, expired or replayed code
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrTwoFactorLocked is returned after too many wrong codes in a row
+	ErrTwoFactorLocked = errors.New("too many invalid two-factor codes, try again later")
+)
+
+type TwoFactorService struct {
+	userRepo     *repositories.UserRepository
+	settingsRepo *repositories.SettingsRepository
+	config       *config.Config
+}
+
+func NewTwoFactorService(
+	userRepo *repositories.UserRepository,
+	settingsRepo *repositories.SettingsRepository,
+	cfg *config.Config,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:     userRepo,
+		settingsRepo: settingsRepo,
+		config:       cfg,
+	}
+}
+
+// TwoFactorStatus describes a user's 2FA setup
+type TwoFactorStatus struct {
+	Enabled                bool `json:"enabled"`
+	Pending                bool `json:"pending"` // Enrollment started but not confirmed
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+	RequiredForRole        bool `json:"required_for_role"` // The user's role must sign in with 2FA
+}
+
+// TwoFactorEnrollment is what an authenticator app needs to add the account
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`           // Base32, for manual entry
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI encoded in the QR code
+	QRCode          string `json:"qr_code"`          // PNG data URI of the provisioning URI
+}
+
+// GetStatus returns the user's 2FA setup
+func (s *TwoFactorService) GetStatus(userID string) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	required, err := s.RequiredFor(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorStatus{
+		Enabled:                user.TwoFactorEnabled,
+		Pending:                user.TOTPPendingSecret != "",
+		RecoveryCodesRemaining: len(user.RecoveryCodes),
+		RequiredForRole:        required,
+	}, nil
+}
+
+// BeginEnrollment generates a new TOTP secret; it is only enabled once ConfirmEnrollment
+// receives a valid code from it, so a half-finished setup cannot lock the user out
+func (s *TwoFactorService) BeginEnrollment(userID string) (*TwoFactorEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.config.TOTPIssuer,
+		AccountName: user.Username,
+		Period:      totpPeriod,
+		Digits:      totpDigits,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := key.Image(256, 256)
+	if err != nil {
+		return nil, err
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, image); err != nil {
+		return nil, err
+	}
+
+	user.TOTPPendingSecret = key.Secret()
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:          key.Secret(),
+		ProvisioningURI: key.URL(),
+		QRCode:          "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr.Bytes()),
+	}, nil
+}
+
+// ConfirmEnrollment enables 2FA once the user proves their app generates valid
+// codes, and returns the recovery codes; they are only shown this once
+func (s *TwoFactorService) ConfirmEnrollment(userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPPendingSecret == "" {
+		return nil, errors.New("start enrollment first")
+	}
+
+	step, ok := matchTOTP(user.TOTPPendingSecret, normalizeCode(code), 0, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TwoFactorEnabled = true
+	user.TOTPSecret = user.TOTPPendingSecret
+	user.TOTPPendingSecret = ""
+	user.TOTPLastStep = step
+	user.RecoveryCodes = hashes
+	user.TwoFactorFailures = 0
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns 2FA off after checking the password and a current or recovery code
+func (s *TwoFactorService) Disable(userID, password, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if !user.TwoFactorEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return errors.New("password is incorrect")
+	}
+	if err := s.VerifyCode(user, code); err != nil {
+		return err
+	}
+
+	required, err := s.RequiredFor(user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.New("two-factor authentication is required for your role")
+	}
+
+	return s.userRepo.DisableTwoFactor(user.ID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a current code
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	if err := s.VerifyCode(user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetRecoveryCodes(user.ID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyCode checks a TOTP code, or consumes a recovery code, for a user with 2FA
+// enabled. A TOTP code is accepted once; after maxTwoFactorFailures wrong codes in
+// a row every code is rejected for twoFactorLockout. Every attempt is counted before
+// the code is checked and a valid code clears the count, so concurrent attempts
+// cannot guess past the lockout or use the same code twice.
+func (s *TwoFactorService) VerifyCode(user *models.User, code string) error {
+	now := time.Now()
+	counted, err := s.userRepo.RecordTwoFactorAttempt(user.ID, maxTwoFactorFailures, now.Add(-twoFactorLockout), now)
+	if err != nil {
+		return err
+	}
+	if !counted {
+		return ErrTwoFactorLocked
+	}
+
+	code = normalizeCode(code)
+	if step, ok := matchTOTP(user.TOTPSecret, code, user.TOTPLastStep, now); ok {
+		used, err := s.userRepo.UseTOTPStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if used {
+			user.TOTPLastStep = step
+			return nil
+		}
+	} else if i := findRecoveryCode(user.RecoveryCodes, code); i >= 0 {
+		used, err := s.userRepo.UseRecoveryCode(user.ID, user.RecoveryCodes[i])
+		if err != nil {
+			return err
+		}
+		if used {
+			user.RecoveryCodes = append(user.RecoveryCodes[:i], user.RecoveryCodes[i+1:]...)
+			return nil
+		}
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// RequiredFor reports whether users with the given role must sign in with 2FA
+func (s *TwoFactorService) RequiredFor(role string) (bool, error) {
+	if role != "admin" {
+		return false, nil
+	}
+	settings, err := s.settingsRepo.GetSecuritySettings()
+	if err != nil {
+		return false, err
+	}
+	return settings.RequireAdminTwoFactor, nil
+}
+
+// AdminsRequireTwoFactor reports whether admin routes need a session signed in with
+// 2FA. Errors count as required, so an unreachable database does not open the admin API.
+func (s *TwoFactorService) AdminsRequireTwoFactor() bool {
+	required, err := s.RequiredFor("admin")
+	return required || err != nil
+}
+
+// GetSecuritySettings returns the security policies
+func (s *TwoFactorService) GetSecuritySettings() (*models.SecuritySettings, error) {
+	return s.settingsRepo.GetSecuritySettings()
+}
+
+// SetAdminRequirement changes whether admins must sign in with 2FA. Turning it on
+// needs a session signed in with 2FA, so admins cannot lock themselves out.
+func (s *TwoFactorService) SetAdminRequirement(required bool, adminID primitive.ObjectID, sessionTwoFactor bool) (*models.SecuritySettings, error) {
+	if required && !sessionTwoFactor {
+		return nil, errors.New("sign in with two-factor authentication before requiring it for admins")
+	}
+
+	settings, err := s.settingsRepo.GetSecuritySettings()
+	if err != nil {
+		return nil, err
+	}
+	settings.RequireAdminTwoFactor = required
+	settings.UpdatedBy = adminID
+	if err := s.settingsRepo.SaveSecuritySettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// normalizeCode strips spaces and dashes and lowercases a TOTP or recovery code
+func normalizeCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ReplaceAll(code, "-", "")
+}
+
+// matchTOTP checks a code against the time steps around now and returns the matching
+// step. Steps at or before lastStep are rejected so a code cannot be used twice.
+func matchTOTP(secret, code string, lastStep int64, now time.Time) (int64, bool) {
+	if secret == "" || len(code) != totpDigits.Length() {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := hotp.GenerateCodeCustom(secret, uint64(step), hotp.ValidateOpts{
+			Digits:    totpDigits,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func hashRecoveryCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+// findRecoveryCode returns the index of the code's hash, or -1
+func findRecoveryCode(hashes []string, code string) int {
+	if code == "" {
+		return -1
+	}
+	hash := hashRecoveryCode(code)
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			return i
+		}
+	}
+	return -1
+}
+
+// generateRecoveryCodes returns new recovery codes, formatted xxxxx-xxxxx, and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(raw))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
@@ -0,0 +1,154 @@
+//go:build integration
+
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+	"github.com/go-ctf-platform/backend/internal/models"
+	"github.com/go-ctf-platform/backend/internal/repositories"
+	"github.com/pquerna/otp/totp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// createTwoFactorUser creates a user with 2FA enabled and returns the TOTP secret
+// and a recovery code
+func createTwoFactorUser(t *testing.T) (*models.User, string, string) {
+	t.Helper()
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "player"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &models.User{
+		ID:               primitive.NewObjectID(),
+		Username:         "player" + primitive.NewObjectID().Hex(),
+		Email:            primitive.NewObjectID().Hex() + "@example.com",
+		Role:             "user",
+		EmailVerified:    true,
+		TwoFactorEnabled: true,
+		TOTPSecret:       key.Secret(),
+		RecoveryCodes:    hashes,
+	}
+	if err := repositories.NewUserRepository().CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	return user, key.Secret(), codes[0]
+}
+
+// verifyConcurrently checks the code n times at once, each with its own copy of
+// the user as read from the database, and returns the errors
+func verifyConcurrently(t *testing.T, s *TwoFactorService, userID primitive.ObjectID, n int, code func(i int) string) []error {
+	t.Helper()
+
+	users := make([]*models.User, n)
+	for i := range users {
+		user, err := repositories.NewUserRepository().FindByID(userID.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		users[i] = user
+	}
+
+	errs := make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range users {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = s.VerifyCode(users[i], code(i))
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+func countAccepted(t *testing.T, errs []error) int {
+	t.Helper()
+
+	accepted := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			accepted++
+		case errors.Is(err, ErrInvalidTwoFactorCode), errors.Is(err, ErrTwoFactorLocked):
+		default:
+			t.Fatalf("VerifyCode: %v", err)
+		}
+	}
+	return accepted
+}
+
+func TestVerifyCodeConcurrentTOTPAcceptedOnce(t *testing.T) {
+	setupTestDB(t)
+	s := NewTwoFactorService(repositories.NewUserRepository(), nil, &config.Config{})
+	user, secret, _ := createTwoFactorUser(t)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := verifyConcurrently(t, s, user.ID, 8, func(int) string { return code })
+	if accepted := countAccepted(t, errs); accepted != 1 {
+		t.Errorf("code accepted %d times, want 1", accepted)
+	}
+}
+
+func TestVerifyCodeConcurrentRecoveryCodeAcceptedOnce(t *testing.T) {
+	setupTestDB(t)
+	s := NewTwoFactorService(repositories.NewUserRepository(), nil, &config.Config{})
+	user, _, recoveryCode := createTwoFactorUser(t)
+
+	errs := verifyConcurrently(t, s, user.ID, 8, func(int) string { return recoveryCode })
+	if accepted := countAccepted(t, errs); accepted != 1 {
+		t.Errorf("recovery code accepted %d times, want 1", accepted)
+	}
+
+	stored, err := repositories.NewUserRepository().FindByID(user.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.RecoveryCodes) != RecoveryCodeCount-1 {
+		t.Errorf("%d recovery codes left, want %d", len(stored.RecoveryCodes), RecoveryCodeCount-1)
+	}
+}
+
+func TestVerifyCodeConcurrentGuessesLockOut(t *testing.T) {
+	setupTestDB(t)
+	s := NewTwoFactorService(repositories.NewUserRepository(), nil, &config.Config{})
+	user, _, _ := createTwoFactorUser(t)
+
+	// Wrong codes from many requests at once only get maxTwoFactorFailures checks
+	errs := verifyConcurrently(t, s, user.ID, 20, func(int) string { return "xxxxx-xxxxx" })
+	checked := 0
+	for _, err := range errs {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			checked++
+		} else if !errors.Is(err, ErrTwoFactorLocked) {
+			t.Fatalf("VerifyCode: %v", err)
+		}
+	}
+	if checked != maxTwoFactorFailures {
+		t.Errorf("%d codes checked, want %d", checked, maxTwoFactorFailures)
+	}
+
+	stored, err := repositories.NewUserRepository().FindByID(user.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyCode(stored, "xxxxx-xxxxx"); !errors.Is(err, ErrTwoFactorLocked) {
+		t.Errorf("VerifyCode after lockout = %v, want ErrTwoFactorLocked", err)
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// LocalStorage stores files on the local disk under a base directory
+type LocalStorage struct {
+	basePath string
+}
+
+func NewLocalStorage(basePath string) (*LocalStorage, error) {
+	if err := os.MkdirAll(basePath, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{basePath: basePath}, nil
+}
+
+// path resolves a key to a file path; cleaning it as an absolute path first
+// keeps ".." segments from escaping the base directory
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.basePath, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see partial uploads
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
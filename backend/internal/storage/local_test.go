@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, store)
+}
+
+func TestLocalStorageKeepsKeysInBaseDir(t *testing.T) {
+	root := t.TempDir()
+	base := filepath.Join(root, "uploads")
+	store, err := NewLocalStorage(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := "escaped?"
+	if err := store.Put(context.Background(), "../../outside", strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "outside")); !os.IsNotExist(err) {
+		t.Errorf("file written outside the base directory")
+	}
+	if _, err := os.Stat(filepath.Join(base, "outside")); err != nil {
+		t.Errorf("file not written inside the base directory: %v", err)
+	}
+}
+
+func TestLocalStorageLeavesNoTemporaryFiles(t *testing.T) {
+	base := t.TempDir()
+	store, err := NewLocalStorage(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := "flag{tmp}"
+	if err := store.Put(context.Background(), "a/b", strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(base, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "b" {
+		t.Errorf("directory holds %v, want only b", entries)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build integration
+
+package storage
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestS3Storage runs against MinIO or another S3-compatible server, e.g.
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	S3_TEST_ENDPOINT=localhost:9000 S3_TEST_ACCESS_KEY=minioadmin S3_TEST_SECRET_KEY=minioadmin \
+//		go test -tags integration ./internal/storage/
+//
+// Each run uses a new bucket, which is removed when the test ends.
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	useSSL, _ := strconv.ParseBool(os.Getenv("S3_TEST_USE_SSL"))
+
+	store, err := NewS3Storage(endpoint, os.Getenv("S3_TEST_ACCESS_KEY"), os.Getenv("S3_TEST_SECRET_KEY"),
+		"ctf-test-"+primitive.NewObjectID().Hex(), "us-east-1", useSSL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := store.client.RemoveBucket(context.Background(), store.bucket); err != nil {
+			t.Errorf("failed to remove test bucket: %v", err)
+		}
+	})
+	testStorage(t, store)
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-ctf-platform/backend/internal/config"
+)
+
+// ErrNotFound is returned when an object does not exist in the store
+var ErrNotFound = errors.New("file not found")
+
+// Storage is a blob store for challenge attachments
+type Storage interface {
+	// Put stores size bytes read from r under key
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key; the caller must close it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key; missing objects are not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage driver selected by cfg.StorageDriver
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalStorage(cfg.StorageLocalPath)
+	case "s3":
+		return NewS3Storage(cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3Bucket, cfg.S3Region, cfg.S3UseSSL)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testStorage runs the behaviour every driver must share against store
+func testStorage(t *testing.T, store Storage) {
+	t.Helper()
+	ctx := context.Background()
+	key := "challenges/test/file"
+
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a missing key = %v, want ErrNotFound", err)
+	}
+
+	content := "flag{stored}"
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	assertContent(t, store, key, content)
+
+	// Putting again replaces the object
+	content = "flag{replaced}"
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	assertContent(t, store, key, content)
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of a missing key: %v", err)
+	}
+}
+
+func assertContent(t *testing.T, store Storage, key, want string) {
+	t.Helper()
+
+	r, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+}
@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"strings"
 )
 
 // HashFlag creates a SHA-256 hash of the flag
@@ -16,3 +19,31 @@ func VerifyFlag(submittedFlag, storedHash string) bool {
 	submittedHash := HashFlag(submittedFlag)
 	return submittedHash == storedHash
 }
+
+// GenerateFlagSecret creates a random per-challenge secret for dynamic flags
+func GenerateFlagSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// DeriveDynamicFlag derives the flag of one team (or teamless user) for a challenge
+// as HMAC-SHA256(secret, challengeID:ownerID), wrapped in format (e.g. "flag{%s}")
+func DeriveDynamicFlag(secret, format, challengeID, ownerID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(challengeID + ":" + ownerID))
+	token := hex.EncodeToString(mac.Sum(nil))[:32]
+
+	if !strings.Contains(format, "%s") {
+		format = "flag{%s}"
+	}
+	return strings.Replace(format, "%s", token, 1)
+}
+
+// VerifyDynamicFlag checks a submitted flag against the owner's derived flag in constant time
+func VerifyDynamicFlag(submittedFlag, secret, format, challengeID, ownerID string) bool {
+	expected := DeriveDynamicFlag(secret, format, challengeID, ownerID)
+	return hmac.Equal([]byte(submittedFlag), []byte(expected))
+}
@@ -3,6 +3,7 @@ import { provideRouter } from '@angular/router';
 import { provideHttpClient, withFetch, withInterceptors } from '@angular/common/http';
 import { provideAnimationsAsync } from '@angular/platform-browser/animations/async';
 import { credentialsInterceptor } from './interceptors/credentials.interceptor';
+import { refreshInterceptor } from './interceptors/refresh.interceptor';
 import 'zone.js';
 
 import { routes } from './app.routes';
@@ -13,7 +14,7 @@ export const appConfig: ApplicationConfig = {
     provideRouter(routes),
     provideHttpClient(
       withFetch(),
-      withInterceptors([credentialsInterceptor])
+      withInterceptors([credentialsInterceptor, refreshInterceptor])
     ),
     provideAnimationsAsync()
   ]
@@ -0,0 +1,40 @@
+import { HttpClient, HttpErrorResponse, HttpInterceptorFn } from '@angular/common/http';
+import { inject } from '@angular/core';
+import { Observable, throwError } from 'rxjs';
+import { catchError, finalize, shareReplay, switchMap } from 'rxjs/operators';
+import { environment } from '../../environments/environment';
+
+// Auth endpoints whose 401s must not trigger a refresh
+const skipRefresh = ['/auth/login', '/auth/register', '/auth/refresh', '/auth/logout'];
+
+// Concurrent 401s share one refresh, since each refresh token works only once
+let refreshInFlight: Observable<unknown> | null = null;
+
+export const refreshInterceptor: HttpInterceptorFn = (req, next) => {
+  if (skipRefresh.some(path => req.url.includes(path))) {
+    return next(req);
+  }
+
+  const http = inject(HttpClient);
+
+  return next(req).pipe(
+    catchError((error: unknown) => {
+      if (!(error instanceof HttpErrorResponse) || error.status !== 401) {
+        return throwError(() => error);
+      }
+
+      // The access token expired: renew it from the refresh token cookie and retry once
+      if (!refreshInFlight) {
+        refreshInFlight = http.post(`${environment.apiUrl}/auth/refresh`, {}, { withCredentials: true }).pipe(
+          finalize(() => { refreshInFlight = null; }),
+          shareReplay(1)
+        );
+      }
+
+      return refreshInFlight.pipe(
+        switchMap(() => next(req)),
+        catchError(() => throwError(() => error))
+      );
+    })
+  );
+};